@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+)
+
+// defaultLanguage is the language texts are shown in unless -lang or set language says otherwise
+const defaultLanguage = "en"
+
+// commandAbility describes an ability in the language set, and lists the pokemon that can have it
+func commandAbility(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	abilityName := strings.ToLower(cmd.Args[0])
+	ability, err := config.client.GetAbility(ctx, abilityName)
+	if err != nil {
+		return nil, apiError(err, "ability", abilityName)
+	}
+
+	result := abilityResult{
+		Name:              ability.Name,
+		DisplayName:       localName(ability.Names, config.language, ability.Name),
+		RequestedLanguage: config.language,
+		Pokemon:           []abilityHolder{},
+	}
+	flavorTexts := make([]flavorText, 0, len(ability.FlavorTextEntries))
+	for _, entry := range ability.FlavorTextEntries {
+		flavorTexts = append(flavorTexts, flavorText{Text: entry.FlavorText, Language: entry.Language.Name})
+	}
+	description := describe(config.language, ability.EffectEntries, flavorTexts)
+	result.Effect, result.ShortEffect, result.Language = description.Effect, description.ShortEffect, description.Language.Name
+
+	for _, holder := range ability.Pokemon {
+		result.Pokemon = append(result.Pokemon, abilityHolder{Name: holder.Pokemon.Name, Hidden: holder.IsHidden})
+	}
+	return result, nil
+}
+
+// speciesAbilities lists the abilities a pokemon can have, in slot order
+func speciesAbilities(pokemon pokeapi.PokeAPIPokemonResponse) []abilityHolder {
+	abilities := []abilityHolder{}
+	for _, ability := range pokemon.Abilities {
+		abilities = append(abilities, abilityHolder{Name: ability.Ability.Name, Hidden: ability.IsHidden})
+	}
+	return abilities
+}
+
+// localName is the name in language, or fallback when the PokeAPI doesn't have one in it
+func localName(names []pokeapi.Name, language, fallback string) string {
+	for _, name := range names {
+		if sameLanguage(name.Language.Name, language) {
+			return name.Name
+		}
+	}
+	return fallback
+}
+
+// flavorText is the description of an ability or item in one of the games, in one language
+type flavorText struct {
+	Text     string
+	Language string
+}
+
+// describe picks the text to describe an ability or item with. The effect texts are only
+// written in a few languages, the in game descriptions (flavor text) in more of them, so it's
+// the effect in language, else the most recent flavor text in it, else the same in English.
+// A flavor text is returned as the short effect
+func describe(language string, effects []pokeapi.VerboseEffect, flavorTexts []flavorText) pokeapi.VerboseEffect {
+	for _, language := range []string{language, defaultLanguage} {
+		for _, entry := range effects {
+			if sameLanguage(entry.Language.Name, language) {
+				return entry
+			}
+		}
+		for i := len(flavorTexts) - 1; i >= 0; i-- {
+			if sameLanguage(flavorTexts[i].Language, language) {
+				return pokeapi.VerboseEffect{
+					ShortEffect: strings.Join(strings.Fields(flavorTexts[i].Text), " "),
+					Language:    pokeapi.NamedAPIResource{Name: flavorTexts[i].Language},
+				}
+			}
+		}
+	}
+	return pokeapi.VerboseEffect{}
+}
+
+// PokeAPI language codes are lowercase except for a few like zh-Hans
+func sameLanguage(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/catch"
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/items"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+	"github.com/staf3333/pokedexcli/internal/stats"
+)
+
+// bagPockets maps the PokeAPI's item pockets to the pockets of the bag, anything not
+// listed (machines, mail, battle items, ...) goes with the other items
+var bagPockets = map[string]string{
+	"medicine":  savefile.PocketMedicine,
+	"pokeballs": savefile.PocketBalls,
+	"berries":   savefile.PocketBerries,
+	"key":       savefile.PocketKeyItems,
+}
+
+// commandBag lists the items in the bag, pocket by pocket or only the pocket given
+func commandBag(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	pockets := savefile.Pockets
+	if len(cmd.Args) > 0 {
+		pocket := strings.ToLower(cmd.Args[0])
+		if !slices.Contains(savefile.Pockets, pocket) {
+			return nil, fmt.Errorf("there's no %s pocket, pick one of %s", pocket, strings.Join(savefile.Pockets, ", "))
+		}
+		pockets = []string{pocket}
+	}
+	result := bagResult{Money: config.save.Money, Items: []savefile.Item{}}
+	for _, pocket := range pockets {
+		result.Items = append(result.Items, config.save.Pocket(pocket)...)
+	}
+	return result, nil
+}
+
+// commandItem describes an item in the language set, and for a berry the plant it grows on
+func commandItem(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	itemName := strings.ToLower(cmd.Args[0])
+	item, err := config.client.GetItem(ctx, itemName)
+	if err != nil {
+		return nil, apiError(err, "item", itemName)
+	}
+	pocket, err := itemPocket(ctx, config, item)
+	if err != nil {
+		return nil, err
+	}
+	result := itemResult{
+		Name:              item.Name,
+		DisplayName:       localName(item.Names, config.language, item.Name),
+		RequestedLanguage: config.language,
+		Category:          item.Category.Name,
+		Pocket:            pocket,
+		Cost:              item.Cost,
+		Holdable:          holdable(item),
+		InBag:             config.save.ItemCount(item.Name),
+	}
+	flavorTexts := make([]flavorText, 0, len(item.FlavorTextEntries))
+	for _, entry := range item.FlavorTextEntries {
+		flavorTexts = append(flavorTexts, flavorText{Text: entry.Text, Language: entry.Language.Name})
+	}
+	description := describe(config.language, item.EffectEntries, flavorTexts)
+	result.Effect, result.ShortEffect, result.Language = description.Effect, description.ShortEffect, description.Language.Name
+
+	if pocket == savefile.PocketBerries {
+		berryName := strings.TrimSuffix(item.Name, "-berry")
+		berry, err := config.client.GetBerry(ctx, berryName)
+		if err != nil {
+			return nil, apiError(err, "berry", berryName)
+		}
+		result.Berry = &berryInfo{
+			Firmness:         berry.Firmness.Name,
+			Size:             berry.Size,
+			GrowthTime:       berry.GrowthTime,
+			MaxHarvest:       berry.MaxHarvest,
+			NaturalGiftType:  berry.NaturalGiftType.Name,
+			NaturalGiftPower: berry.NaturalGiftPower,
+			Flavors:          map[string]int{},
+		}
+		for _, flavor := range berry.Flavors {
+			if flavor.Potency > 0 {
+				result.Berry.Flavors[flavor.Flavor.Name] = flavor.Potency
+			}
+		}
+	}
+	return result, nil
+}
+
+// commandUse uses an item from the bag: a ball is thrown at the wild pokemon, anything else
+// is used on a caught pokemon with on <id>. In a battle that uses up the turn
+func commandUse(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	if len(cmd.Args) == 2 || (len(cmd.Args) == 3 && cmd.Args[1] != "on") {
+		return nil, fmt.Errorf("usage: use <item> [on <id>]")
+	}
+	itemName := strings.ToLower(cmd.Args[0])
+	if config.save.ItemCount(itemName) == 0 {
+		return nil, fmt.Errorf("you don't have any %s, check your bag", itemName)
+	}
+	if slices.Contains(catch.BallNames(), itemName) {
+		if config.wild == nil {
+			return nil, errors.New("there's no wild pokemon to throw it at, use encounter to look for one")
+		}
+		return throwBall(ctx, config, itemName)
+	}
+	if len(cmd.Args) < 3 {
+		return nil, fmt.Errorf("which pokemon is it for? use %s on <id>", itemName)
+	}
+	pokemon, err := caughtPokemon(ctx, config, cmd.Args[2])
+	if err != nil {
+		return nil, err
+	}
+	if config.battle != nil && config.battle.Player.Fainted() {
+		return nil, fmt.Errorf("%s fainted, send out another pokemon with switch <id> first", config.battle.Player.Name)
+	}
+	item, err := config.client.GetItem(ctx, itemName)
+	if err != nil {
+		return nil, apiError(err, "item", itemName)
+	}
+
+	if item.Category.Name == "evolution" {
+		if config.battle != nil {
+			return nil, errInBattle
+		}
+		result, _, err := evolvePokemon(ctx, config, pokemon, item.Name)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return nil, fmt.Errorf("the %s won't have any effect on %s", item.Name, pokemon.Name)
+		}
+		if err := config.save.RemoveItem(item.Name, 1); err != nil {
+			return nil, err
+		}
+		if err := config.save.Write(config.savePath); err != nil {
+			return nil, fmt.Errorf("couldn't save the bag: %w", err)
+		}
+		return *result, nil
+	}
+
+	effect, ok := items.Lookup(item.Name)
+	if !ok {
+		return nil, fmt.Errorf("%s can't be used on a pokemon", item.Name)
+	}
+	message, err := useItem(effect, &pokemon, cmd.Flags["move"])
+	if err != nil {
+		return nil, fmt.Errorf("the %s won't have any effect: %w", item.Name, err)
+	}
+	if err := config.save.RemoveItem(item.Name, 1); err != nil {
+		return nil, err
+	}
+	if err := config.save.Update(pokemon); err != nil {
+		return nil, err
+	}
+	result := useResult{Item: item.Name, ID: pokemon.ID, Pokemon: pokemon.Name, Message: message}
+	if b := config.battle; b != nil {
+		if b.Player.ID == pokemon.ID {
+			b.Player.HP = max(b.Player.MaxHP()-pokemon.Damage, 0)
+			for i := range b.Player.Moves {
+				for _, known := range pokemon.Moves {
+					if known.Name == b.Player.Moves[i].Name {
+						b.Player.Moves[i].PP = known.PP
+					}
+				}
+			}
+		}
+		result.Battle, err = wildTurnLines(config)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, fmt.Errorf("couldn't save the bag: %w", err)
+	}
+	return result, nil
+}
+
+// useItem applies the effect of an item to a pokemon and says what happened. An item that
+// wouldn't do anything is an error, so it isn't wasted. moveName is the move to restore the
+// PP of, for items that restore a single move
+func useItem(effect items.Effect, pokemon *savefile.Pokemon, moveName string) (string, error) {
+	switch {
+	case effect.Levels > 0:
+		if pokemon.Level >= stats.MaxLevel {
+			return "", fmt.Errorf("%s is already level %d", pokemon.Name, stats.MaxLevel)
+		}
+		pokemon.Level = min(pokemon.Level+effect.Levels, stats.MaxLevel)
+		return fmt.Sprintf("%s grew to level %d!", pokemon.Name, pokemon.Level), nil
+	case effect.RestoresPP():
+		if !effect.AllMoves && !pokemon.Knows(moveName) {
+			names := make([]string, 0, len(pokemon.Moves))
+			for _, move := range pokemon.Moves {
+				names = append(names, move.Name)
+			}
+			return "", fmt.Errorf("pick a move with --move, %s knows %s", pokemon.Name, strings.Join(names, ", "))
+		}
+		restored := 0
+		for i := range pokemon.Moves {
+			move := &pokemon.Moves[i]
+			if effect.AllMoves || move.Name == moveName {
+				pp := effect.RestoredPP(move.PP, move.MaxPP)
+				move.PP += pp
+				restored += pp
+			}
+		}
+		if restored == 0 {
+			return "", fmt.Errorf("%s has all its PP", pokemon.Name)
+		}
+		return fmt.Sprintf("%s's PP was restored", pokemon.Name), nil
+	}
+
+	fullHP := maxHP(*pokemon)
+	healed := effect.Healed(fullHP, pokemon.Damage)
+	switch {
+	case healed == 0 && pokemon.Damage >= fullHP:
+		return "", fmt.Errorf("%s has fainted", pokemon.Name)
+	case healed == 0 && effect.Revive:
+		return "", fmt.Errorf("%s hasn't fainted", pokemon.Name)
+	case healed == 0:
+		return "", fmt.Errorf("%s is at full health", pokemon.Name)
+	}
+	fainted := pokemon.Damage >= fullHP
+	pokemon.Damage -= healed
+	if fainted {
+		return fmt.Sprintf("%s was revived with %d HP!", pokemon.Name, healed), nil
+	}
+	return fmt.Sprintf("%s recovered %d HP", pokemon.Name, healed), nil
+}
+
+// commandGive has a caught pokemon hold an item from the bag, whatever it held before goes
+// back in the bag
+func commandGive(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	itemName := strings.ToLower(cmd.Args[0])
+	id, err := parseID(cmd.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	pokemon, _, ok := config.save.Get(id)
+	if !ok {
+		return nil, savefile.ErrNoPokemon
+	}
+	if config.save.ItemCount(itemName) == 0 {
+		return nil, fmt.Errorf("you don't have any %s, check your bag", itemName)
+	}
+	item, err := config.client.GetItem(ctx, itemName)
+	if err != nil {
+		return nil, apiError(err, "item", itemName)
+	}
+	if !holdable(item) {
+		return nil, fmt.Errorf("%s can't be held", item.Name)
+	}
+	if pokemon.HeldItem == item.Name {
+		return nil, fmt.Errorf("%s is already holding a %s", pokemon.Name, item.Name)
+	}
+
+	// the item being taken back is looked up before anything changes, so a failed lookup
+	// doesn't cost the item being given
+	var heldPocket string
+	if pokemon.HeldItem != "" {
+		heldPocket, err = bagPocket(ctx, config, pokemon.HeldItem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := config.save.RemoveItem(item.Name, 1); err != nil {
+		return nil, err
+	}
+	result := giveResult{Item: item.Name, ID: pokemon.ID, Pokemon: pokemon.Name, TookBack: pokemon.HeldItem}
+	if pokemon.HeldItem != "" {
+		if err := config.save.AddItem(pokemon.HeldItem, heldPocket, 1); err != nil {
+			return nil, err
+		}
+	}
+	pokemon.HeldItem = item.Name
+	if err := config.save.Update(pokemon); err != nil {
+		return nil, err
+	}
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, fmt.Errorf("couldn't save the bag: %w", err)
+	}
+	return result, nil
+}
+
+// bagPocket is the pocket an item goes in when it's put in the bag, only looked up when the
+// bag doesn't hold any yet, otherwise it's empty and the item joins the ones already there
+func bagPocket(ctx context.Context, config *config, itemName string) (string, error) {
+	if config.save.ItemCount(itemName) > 0 {
+		return "", nil
+	}
+	item, err := config.client.GetItem(ctx, itemName)
+	if err != nil {
+		return "", apiError(err, "item", itemName)
+	}
+	return itemPocket(ctx, config, item)
+}
+
+// itemPocket is the pocket of the bag an item goes in, which depends on its category
+func itemPocket(ctx context.Context, config *config, item pokeapi.PokeAPIItemResponse) (string, error) {
+	category, err := config.client.GetItemCategory(ctx, item.Category.Name)
+	if err != nil {
+		return "", apiError(err, "item category", item.Category.Name)
+	}
+	if pocket, ok := bagPockets[category.Pocket.Name]; ok {
+		return pocket, nil
+	}
+	return savefile.PocketItems, nil
+}
+
+// holdable reports whether a pokemon can hold the item
+func holdable(item pokeapi.PokeAPIItemResponse) bool {
+	return slices.ContainsFunc(item.Attributes, func(attribute pokeapi.NamedAPIResource) bool {
+		return strings.HasPrefix(attribute.Name, "holdable")
+	})
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/battle"
+	"github.com/staf3333/pokedexcli/internal/catch"
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+)
+
+var (
+	errInBattle    = errors.New("you're in a battle! fight <move>, switch <id>, ball <type> or flee")
+	errNotInBattle = errors.New("you're not in a battle, use encounter to find a wild pokemon")
+)
+
+// startBattle sends out the first party pokemon that can still fight against the wild
+// pokemon. Without one there's no battle and the wild pokemon can only be caught
+func startBattle(ctx context.Context, config *config) (*battle.Battle, error) {
+	var lead *savefile.Pokemon
+	for i := range config.save.Party {
+		if config.save.Party[i].Damage < maxHP(config.save.Party[i]) {
+			lead = &config.save.Party[i]
+			break
+		}
+	}
+	if lead == nil {
+		return nil, nil
+	}
+	chart, err := loadTypeChart(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	pokemonResponse, err := config.client.GetPokemon(ctx, config.wild.Pokemon)
+	if err != nil {
+		return nil, apiError(err, "Pokémon", config.wild.Pokemon)
+	}
+	wild, err := combatant(ctx, config, snapshotPokemon(pokemonResponse, config.wild, config.now()))
+	if err != nil {
+		return nil, err
+	}
+	player, err := combatant(ctx, config, *lead)
+	if err != nil {
+		return nil, err
+	}
+	config.battle = battle.New(config.rng, chart, player, wild)
+	return config.battle, nil
+}
+
+// combatant gets a pokemon ready to battle: its actual stats, the HP it has left and the
+// moves it knows with the PP they have left
+func combatant(ctx context.Context, config *config, pokemon savefile.Pokemon) (*battle.Pokemon, error) {
+	if err := ensureMoves(ctx, config, &pokemon); err != nil {
+		return nil, err
+	}
+	moves, err := battleMoves(ctx, config, pokemon.Moves)
+	if err != nil {
+		return nil, err
+	}
+	fighter := &battle.Pokemon{
+		ID:    pokemon.ID,
+		Name:  pokemon.Name,
+		Types: pokemon.Types,
+		Level: pokemon.Level,
+		Stats: map[string]int{},
+		Moves: moves,
+	}
+	for _, stat := range statValues(pokemon) {
+		fighter.Stats[stat.Name] = stat.Value
+	}
+	fighter.HP = max(fighter.MaxHP()-pokemon.Damage, 0)
+	return fighter, nil
+}
+
+// battleMoves looks up the power, accuracy, ... of the moves a pokemon knows
+func battleMoves(ctx context.Context, config *config, known []savefile.Move) ([]battle.Move, error) {
+	moves := make([]battle.Move, 0, len(known))
+	for _, knownMove := range known {
+		move, err := config.client.GetMove(ctx, knownMove.Name)
+		if err != nil {
+			return nil, apiError(err, "move", knownMove.Name)
+		}
+		battleMove := battle.Move{
+			Name:        move.Name,
+			Type:        move.Type.Name,
+			DamageClass: move.DamageClass.Name,
+			Priority:    move.Priority,
+			PP:          knownMove.PP,
+		}
+		if move.Power != nil {
+			battleMove.Power = *move.Power
+		}
+		if move.Accuracy != nil {
+			battleMove.Accuracy = *move.Accuracy
+		}
+		moves = append(moves, battleMove)
+	}
+	return moves, nil
+}
+
+// syncPP copies the PP left in battle back to the moves a pokemon knows
+func syncPP(known []savefile.Move, fighter *battle.Pokemon) {
+	for i := range known {
+		if move, ok := fighter.Move(known[i].Name); ok {
+			known[i].PP = move.PP
+		}
+	}
+}
+
+// maxHP is the HP of a pokemon at full health
+func maxHP(pokemon savefile.Pokemon) int {
+	for _, stat := range statValues(pokemon) {
+		if stat.Name == "hp" {
+			return stat.Value
+		}
+	}
+	return 0
+}
+
+func commandFight(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	b := config.battle
+	if b == nil {
+		return nil, errNotInBattle
+	}
+	if b.Player.Fainted() {
+		return nil, fmt.Errorf("%s can't fight, send out another pokemon with switch <id>", b.Player.Name)
+	}
+	var turn strings.Builder
+	move, ok := b.Player.Move(cmd.Args[0])
+	switch {
+	case b.Player.OutOfPP():
+		fmt.Fprintf(&turn, "%s has no PP left for any of its moves!\n", b.Player.Name)
+		move = battle.Struggle
+	case !ok:
+		return nil, fmt.Errorf("%s doesn't know %s, it knows %s", b.Player.Name, cmd.Args[0], strings.Join(moveNames(b.Player), ", "))
+	case move.PP == 0:
+		return nil, fmt.Errorf("there's no PP left for %s", move.Name)
+	}
+	for _, hit := range b.Fight(move) {
+		printHit(&turn, hit)
+	}
+	if err := afterTurn(&turn, config); err != nil {
+		return nil, err
+	}
+	return newTurnResult(&turn), nil
+}
+
+// commandSwitch sends out another party pokemon. Unless the last one fainted, that uses up
+// the turn and the wild pokemon attacks the newcomer
+func commandSwitch(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	b := config.battle
+	if b == nil {
+		return nil, errNotInBattle
+	}
+	id, err := parseID(cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	pokemon, place, ok := config.save.Get(id)
+	switch {
+	case !ok || place.Box != 0:
+		return nil, fmt.Errorf("there's no #%d in your party", id)
+	case id == b.Player.ID:
+		return nil, fmt.Errorf("%s is already battling", pokemon.Name)
+	case pokemon.Damage >= maxHP(pokemon):
+		return nil, fmt.Errorf("%s has fainted and can't battle", pokemon.Name)
+	}
+	next, err := combatant(ctx, config, pokemon)
+	if err != nil {
+		return nil, err
+	}
+
+	var turn strings.Builder
+	forced := b.Player.Fainted()
+	if !forced {
+		fmt.Fprintf(&turn, "Come back, %s!\n", b.Player.Name)
+	}
+	b.Switch(next)
+	fmt.Fprintf(&turn, "Go! %s!\n", next.Name)
+	if !forced {
+		if err := wildTurn(&turn, config); err != nil {
+			return nil, err
+		}
+	}
+	return newTurnResult(&turn), nil
+}
+
+// commandBall throws a ball at the wild pokemon being battled
+func commandBall(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	if config.battle == nil {
+		return nil, errNotInBattle
+	}
+	ballName := catch.DefaultBall
+	if len(cmd.Args) > 0 {
+		ballName = cmd.Args[0]
+	}
+	return throwBall(ctx, config, ballName)
+}
+
+// commandFlee tries to get away from the wild pokemon, which gets to attack when it fails
+func commandFlee(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	b := config.battle
+	if b == nil {
+		return nil, errNotInBattle
+	}
+	if b.Player.Fainted() {
+		return nil, fmt.Errorf("%s fainted, send out another pokemon with switch <id> first", b.Player.Name)
+	}
+	var turn strings.Builder
+	if b.Run() {
+		fmt.Fprintln(&turn, "Got away safely!")
+		config.battle, config.wild = nil, nil
+		return newTurnResult(&turn), nil
+	}
+	fmt.Fprintln(&turn, "Couldn't get away!")
+	if err := wildTurn(&turn, config); err != nil {
+		return nil, err
+	}
+	return newTurnResult(&turn), nil
+}
+
+// commandBattle shows how the battle is going
+func commandBattle(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	if config.battle == nil {
+		return nil, errNotInBattle
+	}
+	return newBattleResult(config.battle), nil
+}
+
+// commandHeal restores every pokemon to full health, like a visit to a Pokémon Center
+func commandHeal(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	config.save.Heal()
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, err
+	}
+	return healResult{Pokemon: config.save.Count()}, nil
+}
+
+// wildTurn is the wild pokemon attacking after the player did something other than fight,
+// told to w
+func wildTurn(w io.Writer, config *config) error {
+	printHit(w, config.battle.WildTurn())
+	return afterTurn(w, config)
+}
+
+// wildTurnLines is the wild pokemon's turn after a ball or item was used, told as lines to
+// go after what the ball or item did
+func wildTurnLines(config *config) ([]string, error) {
+	var turn strings.Builder
+	if err := wildTurn(&turn, config); err != nil {
+		return nil, err
+	}
+	return newTurnResult(&turn).Turn, nil
+}
+
+// afterTurn saves the damage the player's pokemon took and ends the battle when one side
+// is out of pokemon, with prize money for beating the wild one
+func afterTurn(w io.Writer, config *config) error {
+	b := config.battle
+	if pokemon, _, ok := config.save.Get(b.Player.ID); ok {
+		pokemon.Damage = b.Player.MaxHP() - b.Player.HP
+		syncPP(pokemon.Moves, b.Player)
+		config.save.Update(pokemon)
+	}
+
+	switch {
+	case b.Wild.Fainted():
+		fmt.Fprintf(w, "The wild %s fainted!\n", b.Wild.Name)
+		if earned := config.save.Earn(b.Prize()); earned > 0 {
+			fmt.Fprintf(w, "You got %s for winning!\n", formatMoney(earned))
+		}
+		config.battle, config.wild = nil, nil
+	case b.Player.Fainted():
+		fmt.Fprintf(w, "%s fainted!\n", b.Player.Name)
+		if able := ablePartyIDs(config); len(able) > 0 {
+			fmt.Fprintf(w, "Send out another pokemon with switch <id>: %s\n", strings.Join(able, ", "))
+		} else {
+			fmt.Fprintln(w, "You have no pokemon left that can fight... you blacked out! Use heal to patch them up")
+			config.battle, config.wild = nil, nil
+		}
+	}
+	return config.save.Write(config.savePath)
+}
+
+// ablePartyIDs lists the party pokemon that can still battle, as #id
+func ablePartyIDs(config *config) []string {
+	var ids []string
+	for _, pokemon := range config.save.Party {
+		if pokemon.Damage < maxHP(pokemon) {
+			ids = append(ids, fmt.Sprintf("#%d %s", pokemon.ID, pokemon.Name))
+		}
+	}
+	return ids
+}
+
+func printHit(w io.Writer, hit battle.Hit) {
+	if hit.Wild {
+		hit.Attacker = "The wild " + hit.Attacker
+	} else {
+		hit.Defender = "the wild " + hit.Defender
+	}
+	fmt.Fprintf(w, "%s used %s!\n", hit.Attacker, hit.Move)
+	switch {
+	case hit.Missed:
+		fmt.Fprintln(w, "  It missed!")
+		return
+	case hit.Effectiveness == 0:
+		fmt.Fprintf(w, "  It doesn't affect %s...\n", hit.Defender)
+		return
+	}
+	if hit.Critical {
+		fmt.Fprintln(w, "  A critical hit!")
+	}
+	switch {
+	case hit.Effectiveness > 1:
+		fmt.Fprintln(w, "  It's super effective!")
+	case hit.Effectiveness < 1:
+		fmt.Fprintln(w, "  It's not very effective...")
+	}
+	if hit.Damage > 0 {
+		fmt.Fprintf(w, "  %s lost %d HP\n", hit.Defender, hit.Damage)
+	} else {
+		// status moves only do damage here, their side effects aren't simulated
+		fmt.Fprintln(w, "  Nothing happened")
+	}
+}
+
+func moveNames(pokemon *battle.Pokemon) []string {
+	names := make([]string, 0, len(pokemon.Moves))
+	for _, move := range pokemon.Moves {
+		names = append(names, move.Name)
+	}
+	return names
+}
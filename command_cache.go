@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/pokecache"
+)
+
+func openDiskCache(ttl time.Duration) (*pokecache.DiskCache, error) {
+	dir, err := pokecache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return pokecache.NewDiskCache(dir, ttl)
+}
+
+func commandCache(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	switch cmd.Args[0] {
+	case "stats":
+		memory := config.memoryCache.Stats()
+		result := cacheStatsResult{Memory: cacheStats{
+			Entries:     memory.Entries,
+			Bytes:       int64(memory.Bytes),
+			Hits:        memory.Hits,
+			Misses:      memory.Misses,
+			Evictions:   memory.Evictions,
+			Expirations: memory.Expirations,
+		}}
+		if config.diskCache == nil {
+			return result, nil
+		}
+		stats, err := config.diskCache.Stats()
+		if err != nil {
+			return nil, err
+		}
+		result.Disk = &cacheStats{Entries: stats.Entries, Bytes: stats.Bytes, Expired: stats.Expired}
+		return result, nil
+	case "clear":
+		config.memoryCache.Clear()
+		result := cacheCleanupResult{Action: "clear", DiskCache: config.diskCache != nil}
+		if config.diskCache == nil {
+			return result, nil
+		}
+		removed, err := config.diskCache.Clear()
+		if err != nil {
+			return nil, err
+		}
+		result.DiskRemoved = removed
+		return result, nil
+	case "prune":
+		result := cacheCleanupResult{Action: "prune", DiskCache: config.diskCache != nil}
+		if config.diskCache == nil {
+			return result, nil
+		}
+		removed, err := config.diskCache.Prune()
+		if err != nil {
+			return nil, err
+		}
+		result.DiskRemoved = removed
+		return result, nil
+	}
+	return nil, fmt.Errorf("unknown cache command '%s', usage: cache <stats|clear|prune>", cmd.Args[0])
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/encounter"
+	"github.com/staf3333/pokedexcli/internal/items"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+	"github.com/staf3333/pokedexcli/internal/stats"
+)
+
+// commandEncounter looks for a wild pokemon in the current area, or moves to the area given
+// first, weighted by the area's encounter table for the game version being
+// played. The pokemon that appears is the one catch can be used on
+func commandEncounter(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	areaName := config.save.Position.Area
+	if len(cmd.Args) > 0 {
+		areaName = cmd.Args[0]
+	}
+	if areaName == "" {
+		return nil, errNowhere
+	}
+	area, err := config.client.GetLocationArea(ctx, areaName)
+	if err != nil {
+		return nil, apiError(err, "location area", areaName)
+	}
+	if err := config.moveToArea(ctx, area); err != nil {
+		return nil, err
+	}
+
+	slots := encounter.Slots(area)
+	group, err := config.gameVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// playing as a particular game, only its encounters count
+	if group != nil {
+		slots = encounter.InVersions(slots, versionNames(group))
+	}
+	method := encounter.DefaultMethod
+	if value, ok := cmd.Flags["method"]; ok {
+		method = value
+	}
+	version := cmd.Flags["version"]
+	if version == "" {
+		// without a version pick the first game the area has this kind of encounter in
+		if versions := encounter.Versions(encounter.Filter(slots, "", method)); len(versions) > 0 {
+			version = versions[0]
+		}
+	}
+
+	found, err := encounter.Roll(config.rng, encounter.Filter(slots, version, method))
+	if errors.Is(err, encounter.ErrNoEncounters) {
+		methods := encounter.Methods(slots)
+		if len(methods) == 0 && group != nil {
+			return nil, fmt.Errorf("there are no wild pokemon in %s in %s", areaName, group.Name)
+		} else if len(methods) == 0 {
+			return nil, fmt.Errorf("there are no wild pokemon in %s", areaName)
+		}
+		return nil, fmt.Errorf("no wild pokemon can be found in %s by %s, try one of %s", areaName, method, strings.Join(methods, ", "))
+	} else if err != nil {
+		return nil, err
+	}
+	wild, err := rollWildPokemon(ctx, config, found)
+	if err != nil {
+		return nil, err
+	}
+	// whatever was here before runs off
+	config.wild = wild
+	seenAt := config.now()
+	config.save.Register(savefile.DexEntry{Number: wild.DexNumber, Name: wild.Pokemon, FirstSeenIn: areaName, FirstSeenAt: &seenAt})
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, fmt.Errorf("couldn't save the pokedex: %w", err)
+	}
+	result := encounterResult{Area: areaName, Encounter: found, Gender: wild.Gender, Shiny: wild.Shiny}
+	// with a pokemon able to fight the wild one has to be dealt with before moving on
+	fight, err := startBattle(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if fight != nil {
+		result.Partner = fight.Player.Name
+	}
+	return result, nil
+}
+
+// wildPokemon is a pokemon encounter turned up. Like in the games, everything about it is
+// decided the moment it appears, catching it just keeps it that way
+type wildPokemon struct {
+	encounter.Encounter
+	// DexNumber is the species' national dex number
+	DexNumber int
+	Nature    stats.Nature
+	IVs       map[string]int
+	Gender    string
+	Shiny     bool
+	// Ability is one of the species' regular abilities, hidden ones don't show up in the wild
+	Ability string
+	// HeldItem is what the pokemon holds, if anything, it comes along when it's caught
+	HeldItem string
+}
+
+// rollWildPokemon decides the nature, IVs, gender, shininess, ability and held item of a
+// pokemon that was found
+func rollWildPokemon(ctx context.Context, config *config, found encounter.Encounter) (*wildPokemon, error) {
+	pokemon, err := config.client.GetPokemon(ctx, found.Pokemon)
+	if err != nil {
+		return nil, apiError(err, "Pokémon", found.Pokemon)
+	}
+	species, err := config.client.GetPokemonSpecies(ctx, pokemon.Species.Name)
+	if err != nil {
+		return nil, apiError(err, "Pokémon species", pokemon.Species.Name)
+	}
+	natures, err := config.client.ListNatures(ctx)
+	if err != nil {
+		return nil, apiError(err, "nature", "")
+	}
+	if len(natures.Results) == 0 {
+		return nil, errors.New("the PokeAPI didn't list any natures")
+	}
+	natureName := natures.Results[config.rng.Intn(len(natures.Results))].Name
+	nature, err := config.client.GetNature(ctx, natureName)
+	if err != nil {
+		return nil, apiError(err, "nature", natureName)
+	}
+
+	wild := &wildPokemon{
+		Encounter: found,
+		DexNumber: species.ID,
+		Nature:    stats.Nature{Name: nature.Name},
+		IVs:       stats.RollIVs(config.rng),
+		Gender:    stats.RollGender(config.rng, species.GenderRate),
+		Shiny:     stats.RollShiny(config.rng),
+	}
+	if nature.IncreasedStat != nil && nature.DecreasedStat != nil {
+		wild.Nature.Increased = nature.IncreasedStat.Name
+		wild.Nature.Decreased = nature.DecreasedStat.Name
+	}
+	var abilities []string
+	for _, ability := range pokemon.Abilities {
+		if !ability.IsHidden {
+			abilities = append(abilities, ability.Ability.Name)
+		}
+	}
+	// only roll when there's a choice, so species with one ability keep the same sequence
+	switch {
+	case len(abilities) == 1:
+		wild.Ability = abilities[0]
+	case len(abilities) > 1:
+		wild.Ability = abilities[config.rng.Intn(len(abilities))]
+	}
+	// how often an item is held differs from game to game
+	var held []items.Held
+	for _, item := range pokemon.HeldItems {
+		for _, detail := range item.VersionDetails {
+			if detail.Version.Name == found.Version {
+				held = append(held, items.Held{Name: item.Item.Name, Rarity: detail.Rarity})
+			}
+		}
+	}
+	wild.HeldItem = items.RollHeld(config.rng, held)
+	return wild, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/evolution"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+)
+
+// commandEvolutions shows the whole evolution family of a pokemon, branches and all
+func commandEvolutions(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	species, chain, err := evolutionChain(ctx, config, strings.ToLower(cmd.Args[0]))
+	if err != nil {
+		return nil, err
+	}
+	return evolutionsResult{Species: species, Chain: evolution.Tree(chain)}, nil
+}
+
+// commandEvolve evolves a caught pokemon into the first species it qualifies for, keeping
+// everything that makes it its own: level, nature, IVs, EVs, ... A held item used to evolve is
+// gone. Evolving with an evolution stone is done by using it, see commandUse
+func commandEvolve(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	id, err := parseID(cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	pokemon, _, ok := config.save.Get(id)
+	if !ok {
+		return nil, savefile.ErrNoPokemon
+	}
+	result, needs, err := evolvePokemon(ctx, config, pokemon, "")
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		return *result, nil
+	}
+	return nil, fmt.Errorf("%s #%d can't evolve yet, it needs\n - %s", pokemon.Name, id, strings.Join(needs, "\n - "))
+}
+
+// evolvePokemon evolves pokemon, with usedItem used on it, into the first species it
+// qualifies for and saves it. When it doesn't qualify for any the result is nil and needs
+// says what each evolution takes
+func evolvePokemon(ctx context.Context, config *config, pokemon savefile.Pokemon, usedItem string) (*evolveResult, []string, error) {
+	species, chain, err := evolutionChain(ctx, config, pokemon.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	link, ok := evolution.Find(chain, species)
+	if !ok || len(link.EvolvesTo) == 0 {
+		return nil, nil, fmt.Errorf("%s doesn't evolve", pokemon.Name)
+	}
+
+	subject := evolution.Subject{Level: pokemon.Level, HeldItem: pokemon.HeldItem, Gender: pokemon.Gender, UsedItem: usedItem}
+	var needs []string
+	for _, next := range link.EvolvesTo {
+		for _, detail := range next.EvolutionDetails {
+			if !evolution.Ready(detail, subject) {
+				needs = append(needs, fmt.Sprintf("%s: %s", next.Species.Name, evolution.Describe(detail)))
+				continue
+			}
+			target, err := defaultPokemon(ctx, config, next.Species.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+			evolved := pokemon
+			setSpecies(&evolved, target)
+			evolved.Ability, err = evolvedAbility(ctx, config, pokemon, target)
+			if err != nil {
+				return nil, nil, err
+			}
+			if evolution.UsesHeldItem(detail) {
+				evolved.HeldItem = ""
+			}
+			if err := config.save.Update(evolved); err != nil {
+				return nil, nil, err
+			}
+			evolvedAt := config.now()
+			config.save.Register(savefile.DexEntry{Number: evolved.SpeciesID, Name: evolved.Name, Caught: true, FirstSeenAt: &evolvedAt})
+			if err := config.save.Write(config.savePath); err != nil {
+				return nil, nil, fmt.Errorf("couldn't save the evolved pokemon: %w", err)
+			}
+			return &evolveResult{ID: pokemon.ID, From: pokemon.Name, To: evolved.Name, How: evolution.Describe(detail)}, nil, nil
+		}
+	}
+	return nil, needs, nil
+}
+
+// evolvedAbility is the ability of the evolved species in the same slot as the one the
+// pokemon had, like in the games. Pokemon without an ability stay without one
+func evolvedAbility(ctx context.Context, config *config, pokemon savefile.Pokemon, target pokeapi.PokeAPIPokemonResponse) (string, error) {
+	if pokemon.Ability == "" {
+		return "", nil
+	}
+	current, err := config.client.GetPokemon(ctx, pokemon.Name)
+	if err != nil {
+		return "", apiError(err, "Pokémon", pokemon.Name)
+	}
+	slot := 1
+	for _, ability := range current.Abilities {
+		if ability.Ability.Name == pokemon.Ability {
+			slot = ability.Slot
+		}
+	}
+	// a species without that slot gets its first ability
+	evolved := pokemon.Ability
+	for _, ability := range target.Abilities {
+		if ability.Slot == slot {
+			return ability.Ability.Name, nil
+		}
+		if ability.Slot == 1 {
+			evolved = ability.Ability.Name
+		}
+	}
+	return evolved, nil
+}
+
+// evolutionChain finds the species of a pokemon and the evolution chain it belongs to
+func evolutionChain(ctx context.Context, config *config, pokemonName string) (string, pokeapi.ChainLink, error) {
+	pokemon, err := config.client.GetPokemon(ctx, pokemonName)
+	if err != nil {
+		return "", pokeapi.ChainLink{}, apiError(err, "Pokémon", pokemonName)
+	}
+	species, err := config.client.GetPokemonSpecies(ctx, pokemon.Species.Name)
+	if err != nil {
+		return "", pokeapi.ChainLink{}, apiError(err, "Pokémon species", pokemon.Species.Name)
+	}
+	chainID, err := pokeapi.ResourceID(species.EvolutionChain.URL)
+	if err != nil {
+		return "", pokeapi.ChainLink{}, err
+	}
+	chain, err := config.client.GetEvolutionChain(ctx, chainID)
+	if err != nil {
+		return "", pokeapi.ChainLink{}, apiError(err, "evolution chain", fmt.Sprint(chainID))
+	}
+	return species.Name, chain.Chain, nil
+}
+
+// defaultPokemon is the usual form of a species, the one a pokemon evolves into
+func defaultPokemon(ctx context.Context, config *config, speciesName string) (pokeapi.PokeAPIPokemonResponse, error) {
+	species, err := config.client.GetPokemonSpecies(ctx, speciesName)
+	if err != nil {
+		return pokeapi.PokeAPIPokemonResponse{}, apiError(err, "Pokémon species", speciesName)
+	}
+	pokemonName := speciesName
+	for _, variety := range species.Varieties {
+		if variety.IsDefault {
+			pokemonName = variety.Pokemon.Name
+		}
+	}
+	pokemon, err := config.client.GetPokemon(ctx, pokemonName)
+	if err != nil {
+		return pokeapi.PokeAPIPokemonResponse{}, apiError(err, "Pokémon", pokemonName)
+	}
+	return pokemon, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/typechart"
+)
+
+// commandMatchup shows how a pokemon or type (water, or water/flying for a dual type) holds
+// up against every type, or with vs how two of them do against each other
+func commandMatchup(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	if len(cmd.Args) == 2 || (len(cmd.Args) == 3 && cmd.Args[1] != "vs") {
+		return nil, fmt.Errorf("usage: matchup <pokemon|type> [vs <pokemon|type>]")
+	}
+	chart, err := loadTypeChart(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := matchupSide(ctx, config, cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(cmd.Args) == 1 {
+		return newMatchupResult(chart, subject), nil
+	}
+	opponent, err := matchupSide(ctx, config, cmd.Args[2])
+	if err != nil {
+		return nil, err
+	}
+	return versusResult{
+		Attacker: subject,
+		Defender: opponent,
+		Offense:  typeMultipliers(chart, subject.Types, opponent.Types),
+		Defense:  typeMultipliers(chart, opponent.Types, subject.Types),
+	}, nil
+}
+
+// matchupSide is either a type, two types separated by a slash, or the types of a pokemon
+func matchupSide(ctx context.Context, config *config, name string) (typedSide, error) {
+	name = strings.ToLower(name)
+	types := strings.Split(name, "/")
+	if len(types) <= 2 && !slices.ContainsFunc(types, func(t string) bool { return !slices.Contains(typechart.Names, t) }) {
+		return typedSide{Name: name, Types: types}, nil
+	}
+	pokemon, err := config.client.GetPokemon(ctx, name)
+	if err != nil {
+		return typedSide{}, apiError(err, "Pokémon or type", name)
+	}
+	side := typedSide{Name: pokemon.Name}
+	for _, pokemonType := range pokemon.Types {
+		side.Types = append(side.Types, pokemonType.Type.Name)
+	}
+	return side, nil
+}
+
+// typeMultipliers is how effective moves of each attacking type are against the defending types
+func typeMultipliers(chart typechart.Chart, attacking, defending []string) []typeMultiplier {
+	multipliers := make([]typeMultiplier, 0, len(attacking))
+	for _, attackingType := range attacking {
+		multipliers = append(multipliers, typeMultiplier{
+			Type:       attackingType,
+			Multiplier: chart.Multiplier(attackingType, defending...),
+		})
+	}
+	return multipliers
+}
+
+// loadTypeChart fills config.typeChart with the damage relations of every type. The
+// responses are cached like any other, so this only goes to the network once
+func loadTypeChart(ctx context.Context, config *config) (typechart.Chart, error) {
+	for _, name := range typechart.Names {
+		if config.typeChart.Has(name) {
+			continue
+		}
+		pokemonType, err := config.client.GetType(ctx, name)
+		if err != nil {
+			return nil, apiError(err, "type", name)
+		}
+		relations := pokemonType.DamageRelations
+		config.typeChart.Add(name, typechart.Relations{
+			DoubleDamageTo: namesOf(relations.DoubleDamageTo),
+			HalfDamageTo:   namesOf(relations.HalfDamageTo),
+			NoDamageTo:     namesOf(relations.NoDamageTo),
+		})
+	}
+	return config.typeChart, nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+)
+
+// commandMoves lists the moves a pokemon can learn, in the version group being played
+// unless --version-group says otherwise, in every game when neither is set
+func commandMoves(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	pokemonName := strings.ToLower(cmd.Args[0])
+	pokemon, err := config.client.GetPokemon(ctx, pokemonName)
+	if err != nil {
+		return nil, apiError(err, "Pokémon", pokemonName)
+	}
+	versionGroup := config.save.GameVersion
+	if value, ok := cmd.Flags["version-group"]; ok {
+		group, err := config.client.GetVersionGroup(ctx, value)
+		if err != nil {
+			return nil, apiError(err, "version group", value)
+		}
+		versionGroup = group.Name
+	}
+
+	result := movesResult{Pokemon: pokemon.Name, Version: versionGroup, Method: cmd.Flags["method"]}
+	result.Moves = learnset(pokemon, versionGroup, result.Method)
+	if len(result.Moves) == 0 {
+		return nil, fmt.Errorf("%s learns no moves that way", pokemon.Name)
+	}
+	return result, nil
+}
+
+// commandTeach teaches a caught pokemon a move from its learnset. A pokemon that already
+// knows MaxMoves moves has to forget one for it, given with --replace
+func commandTeach(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	pokemon, err := caughtPokemon(ctx, config, cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	moveName := strings.ToLower(cmd.Args[1])
+	details, err := config.client.GetPokemon(ctx, pokemon.Name)
+	if err != nil {
+		return nil, apiError(err, "Pokémon", pokemon.Name)
+	}
+	learnable := false
+	for _, move := range learnset(details, config.save.GameVersion, "") {
+		learnable = learnable || move.Name == moveName
+	}
+	if !learnable {
+		return nil, fmt.Errorf("%s can't learn %s, use moves %s to see what it can", pokemon.Name, moveName, pokemon.Name)
+	}
+	move, err := config.client.GetMove(ctx, moveName)
+	if err != nil {
+		return nil, apiError(err, "move", moveName)
+	}
+
+	replacing := strings.ToLower(cmd.Flags["replace"])
+	err = pokemon.Learn(savefile.Move{Name: move.Name, PP: move.PP, MaxPP: move.PP}, replacing)
+	if errors.Is(err, savefile.ErrMovesFull) {
+		return nil, fmt.Errorf("%s: %w, pick one to forget with --replace <move>", pokemon.Name, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", pokemon.Name, err)
+	}
+	if err := saveMoves(config, pokemon); err != nil {
+		return nil, err
+	}
+	return learnResult{ID: pokemon.ID, Pokemon: pokemon.Name, Learned: move.Name, Forgot: replacing}, nil
+}
+
+func commandForget(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	pokemon, err := caughtPokemon(ctx, config, cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	moveName := strings.ToLower(cmd.Args[1])
+	if err := pokemon.Forget(moveName); err != nil {
+		return nil, fmt.Errorf("%s: %w", pokemon.Name, err)
+	}
+	if err := saveMoves(config, pokemon); err != nil {
+		return nil, err
+	}
+	return learnResult{ID: pokemon.ID, Pokemon: pokemon.Name, Forgot: moveName}, nil
+}
+
+// caughtPokemon looks up a caught pokemon by ID, with the moves it knows
+func caughtPokemon(ctx context.Context, config *config, arg string) (savefile.Pokemon, error) {
+	id, err := parseID(arg)
+	if err != nil {
+		return savefile.Pokemon{}, err
+	}
+	pokemon, _, ok := config.save.Get(id)
+	if !ok {
+		return savefile.Pokemon{}, savefile.ErrNoPokemon
+	}
+	return pokemon, ensureMoves(ctx, config, &pokemon)
+}
+
+func saveMoves(config *config, pokemon savefile.Pokemon) error {
+	if err := config.save.Update(pokemon); err != nil {
+		return err
+	}
+	if err := config.save.Write(config.savePath); err != nil {
+		return fmt.Errorf("couldn't save the moves of %s: %w", pokemon.Name, err)
+	}
+	return nil
+}
+
+// ensureMoves gives a pokemon that knows no moves the last ones it learned by leveling up
+// to its level. That's how a wild pokemon starts out, and pokemon saved before movesets
+// were kept get theirs the first time they're needed
+func ensureMoves(ctx context.Context, config *config, pokemon *savefile.Pokemon) error {
+	if len(pokemon.Moves) > 0 {
+		return nil
+	}
+	details, err := config.client.GetPokemon(ctx, pokemon.Name)
+	if err != nil {
+		return apiError(err, "Pokémon", pokemon.Name)
+	}
+	for _, name := range levelUpMoves(details, config.save.GameVersion, pokemon.Level) {
+		move, err := config.client.GetMove(ctx, name)
+		if err != nil {
+			return apiError(err, "move", name)
+		}
+		pokemon.Moves = append(pokemon.Moves, savefile.Move{Name: move.Name, PP: move.PP, MaxPP: move.PP})
+	}
+	if pokemon.ID != 0 {
+		return config.save.Update(*pokemon)
+	}
+	return nil
+}
+
+// levelUpMoves are the names of the last savefile.MaxMoves moves learned by leveling up to
+// level, in the version group being played or, without one, the earliest any game teaches it
+func levelUpMoves(pokemon pokeapi.PokeAPIPokemonResponse, versionGroup string, level int) []string {
+	learnedAt := map[string]int{}
+	for _, move := range learnset(pokemon, versionGroup, "level-up") {
+		if move.Level > level {
+			continue
+		}
+		if at, ok := learnedAt[move.Name]; !ok || move.Level < at {
+			learnedAt[move.Name] = move.Level
+		}
+	}
+	names := make([]string, 0, len(learnedAt))
+	for name := range learnedAt {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if learnedAt[names[i]] != learnedAt[names[j]] {
+			return learnedAt[names[i]] < learnedAt[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names[max(len(names)-savefile.MaxMoves, 0):]
+}
+
+// learnset lists the moves pokemon can learn, level up moves first in the order they're
+// learned, then the rest by how they're learned. An empty versionGroup or method matches
+// any, without a version group every game's way of learning a move is listed
+func learnset(pokemon pokeapi.PokeAPIPokemonResponse, versionGroup, method string) []learnableMove {
+	moves := []learnableMove{}
+	for _, move := range pokemon.Moves {
+		for _, details := range move.VersionGroupDetails {
+			if versionGroup != "" && details.VersionGroup.Name != versionGroup {
+				continue
+			}
+			if method != "" && details.MoveLearnMethod.Name != method {
+				continue
+			}
+			learned := learnableMove{Name: move.Move.Name, Method: details.MoveLearnMethod.Name}
+			if learned.Method == "level-up" {
+				learned.Level = details.LevelLearnedAt
+			}
+			if versionGroup == "" {
+				learned.VersionGroup = details.VersionGroup.Name
+			}
+			moves = append(moves, learned)
+		}
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		if (moves[i].Method == "level-up") != (moves[j].Method == "level-up") {
+			return moves[i].Method == "level-up"
+		}
+		if moves[i].Method != moves[j].Method {
+			return moves[i].Method < moves[j].Method
+		}
+		return moves[i].Level < moves[j].Level
+	})
+	return moves
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+)
+
+// parseID reads a pokemon ID the way party and box show them, "#3" or just "3"
+func parseID(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("'%s' isn't a pokemon ID, party and box show them", arg)
+	}
+	return id, nil
+}
+
+// findPokemon looks a caught pokemon up by ID, or failing that by species name
+func findPokemon(config *config, arg string) (savefile.Pokemon, bool) {
+	if id, err := parseID(arg); err == nil {
+		pokemon, _, ok := config.save.Get(id)
+		return pokemon, ok
+	}
+	return config.save.Find(strings.ToLower(arg))
+}
+
+func commandParty(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	return storageResult{Pokemon: config.save.Party}, nil
+}
+
+// commandBox shows PC box n, box 1 by default
+func commandBox(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	box := 1
+	if len(cmd.Args) > 0 {
+		n, err := strconv.Atoi(cmd.Args[0])
+		if err != nil || n < 1 || n > len(config.save.Boxes) {
+			return nil, fmt.Errorf("there's no box %s, boxes go from 1 to %d", cmd.Args[0], len(config.save.Boxes))
+		}
+		box = n
+	}
+	return storageResult{Box: box, Pokemon: config.save.Boxes[box-1]}, nil
+}
+
+// commandDeposit moves a party pokemon into a box, the first with room unless --box says which
+func commandDeposit(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	id, err := parseID(cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	box := 0
+	if value, ok := cmd.Flags["box"]; ok {
+		if box, err = strconv.Atoi(value); err != nil || box < 1 {
+			return nil, fmt.Errorf("--box must be a box number, got '%s'", value)
+		}
+	}
+	place, err := config.save.Deposit(id, box)
+	if err != nil {
+		return nil, err
+	}
+	return saveStorage(config, id, place)
+}
+
+func commandWithdraw(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	id, err := parseID(cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	place, err := config.save.Withdraw(id)
+	if err != nil {
+		return nil, err
+	}
+	return saveStorage(config, id, place)
+}
+
+// commandSwap trades the places of two pokemon, e.g. to change the party order or to
+// swap a party pokemon for a boxed one in one go
+func commandSwap(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	a, err := parseID(cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := parseID(cmd.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	if err := config.save.Swap(a, b); err != nil {
+		return nil, err
+	}
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, err
+	}
+	return swapResult{Swapped: [2]int{a, b}}, nil
+}
+
+func commandRelease(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	id, err := parseID(cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	pokemon, err := config.save.Release(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, err
+	}
+	return releaseResult{ID: pokemon.ID, Name: pokemon.Name}, nil
+}
+
+// saveStorage writes the save after a pokemon moved and says where it went
+func saveStorage(config *config, id int, place savefile.Place) (any, error) {
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, err
+	}
+	pokemon, _, _ := config.save.Get(id)
+	return movedResult{ID: pokemon.ID, Name: pokemon.Name, Place: place.String()}, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+)
+
+// nationalPokedex is the pokedex with every species in it, completion is measured against
+// it unless a region or generation is asked for
+const nationalPokedex = "national"
+
+// dexSpecies is a species as a pokedex or generation lists it
+type dexSpecies struct {
+	Number int
+	Name   string
+}
+
+// commandPokedex lists the species seen so far in national dex order, with how complete
+// the pokedex is. With a region or generation only the species of it are listed, and
+// with --missing the ones not caught yet are listed instead
+func commandPokedex(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	regionName, byRegion := cmd.Flags["region"]
+	generationName, byGeneration := cmd.Flags["generation"]
+	_, missing := cmd.Flags["missing"]
+	if byRegion && byGeneration {
+		return nil, errors.New("use either --region or --generation, not both")
+	}
+	if !byRegion && !byGeneration && !missing && len(config.save.Pokedex) < 1 {
+		return nil, errors.New("no pokemon in your pokedex yet")
+	}
+	if err := ensureDexNumbers(ctx, config); err != nil {
+		return nil, err
+	}
+
+	result := pokedexResult{Missing: missing, Completion: []completion{}}
+	var listed []dexSpecies
+	switch {
+	case byRegion:
+		region, err := config.client.GetRegion(ctx, regionName)
+		if err != nil {
+			return nil, apiError(err, "region", regionName)
+		}
+		if len(region.Pokedexes) == 0 {
+			return nil, fmt.Errorf("%s doesn't have a pokedex", region.Name)
+		}
+		// a region can have more than one, e.g. kanto's from red-blue and from
+		// firered-leafgreen, the species listed are from the first
+		for i, dex := range region.Pokedexes {
+			species, err := pokedexSpecies(ctx, config, dex.Name)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				result.Pokedex, listed = dex.Name, species
+			}
+			result.Completion = append(result.Completion, dexCompletion(config.save, dex.Name, species))
+		}
+	case byGeneration:
+		generation, err := config.client.GetGeneration(ctx, generationName)
+		if err != nil {
+			return nil, apiError(err, "generation", generationName)
+		}
+		species, err := generationSpecies(generation)
+		if err != nil {
+			return nil, err
+		}
+		result.Pokedex, listed = generation.Name, species
+		result.Completion = append(result.Completion, dexCompletion(config.save, generation.Name, species))
+	default:
+		species, err := pokedexSpecies(ctx, config, nationalPokedex)
+		if err != nil && missing {
+			return nil, err
+		}
+		if missing {
+			result.Pokedex, listed = nationalPokedex, species
+		}
+		// the species seen are all in the save, so they're listed without the completion
+		// when the national pokedex can't be looked up
+		if err == nil {
+			result.Completion = append(result.Completion, dexCompletion(config.save, nationalPokedex, species))
+		}
+	}
+
+	result.Entries = []dexRow{}
+	if listed == nil && !missing {
+		for _, entry := range config.save.Pokedex {
+			result.Entries = append(result.Entries, dexRow{DexEntry: entry, Seen: true})
+		}
+		return result, nil
+	}
+	for _, species := range listed {
+		entry, seen := config.save.Registered(species.Number)
+		if !seen {
+			entry = savefile.DexEntry{Number: species.Number, Name: species.Name}
+		}
+		if (missing && !entry.Caught) || (!missing && seen) {
+			result.Entries = append(result.Entries, dexRow{DexEntry: entry, Seen: seen})
+		}
+	}
+	return result, nil
+}
+
+// ensureDexNumbers looks up the national dex number of species saved before it was kept,
+// so the pokedex can be sorted and compared by it. One that can't be looked up keeps going
+// without a number, at the end of the pokedex, and gets another go next time
+func ensureDexNumbers(ctx context.Context, config *config) error {
+	changed := false
+	// registering sorts the pokedex, so go over a copy
+	for _, entry := range slices.Clone(config.save.Pokedex) {
+		if entry.Number != 0 {
+			continue
+		}
+		// entries are named after the pokemon, which for forms isn't the species' name
+		pokemon, err := config.client.GetPokemon(ctx, entry.Name)
+		if err != nil {
+			continue
+		}
+		species, err := config.client.GetPokemonSpecies(ctx, pokemon.Species.Name)
+		if err != nil {
+			continue
+		}
+		entry.Number = species.ID
+		config.save.Register(entry)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if err := config.save.Write(config.savePath); err != nil {
+		return fmt.Errorf("couldn't save the pokedex: %w", err)
+	}
+	return nil
+}
+
+// pokedexSpecies is every species in a pokedex, in the pokedex's order
+func pokedexSpecies(ctx context.Context, config *config, name string) ([]dexSpecies, error) {
+	pokedex, err := config.client.GetPokedex(ctx, name)
+	if err != nil {
+		return nil, apiError(err, "pokedex", name)
+	}
+	species := []dexSpecies{}
+	for _, entry := range pokedex.PokemonEntries {
+		number, err := pokeapi.ResourceID(entry.PokemonSpecies.URL)
+		if err != nil {
+			return nil, err
+		}
+		species = append(species, dexSpecies{Number: number, Name: entry.PokemonSpecies.Name})
+	}
+	return species, nil
+}
+
+// generationSpecies is every species introduced in a generation, in national dex order
+// since the PokeAPI doesn't list them in any
+func generationSpecies(generation pokeapi.PokeAPIGenerationResponse) ([]dexSpecies, error) {
+	species := []dexSpecies{}
+	for _, resource := range generation.PokemonSpecies {
+		number, err := pokeapi.ResourceID(resource.URL)
+		if err != nil {
+			return nil, err
+		}
+		species = append(species, dexSpecies{Number: number, Name: resource.Name})
+	}
+	sort.Slice(species, func(i, j int) bool {
+		return species[i].Number < species[j].Number
+	})
+	return species, nil
+}
+
+// dexCompletion counts how many of the species have been seen and caught
+func dexCompletion(save *savefile.Save, name string, species []dexSpecies) completion {
+	done := completion{Pokedex: name, Total: len(species)}
+	for _, s := range species {
+		if entry, ok := save.Registered(s.Number); ok {
+			done.Seen++
+			if entry.Caught {
+				done.Caught++
+			}
+		}
+	}
+	if done.Total > 0 {
+		done.Percent = float64(done.Caught) * 100 / float64(done.Total)
+	}
+	return done
+}
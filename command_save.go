@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+	"github.com/staf3333/pokedexcli/internal/stats"
+)
+
+// snapshotPokemon keeps only the parts of the API response we want in the save file,
+// along with what makes the wild pokemon that was caught its own
+func snapshotPokemon(pokemon pokeapi.PokeAPIPokemonResponse, wild *wildPokemon, caughtAt time.Time) savefile.Pokemon {
+	snapshot := savefile.Pokemon{
+		CaughtAt: caughtAt,
+		Level:    wild.Level,
+		Nature:   wild.Nature,
+		IVs:      wild.IVs,
+		EVs:      map[string]int{},
+		Gender:   wild.Gender,
+		Shiny:    wild.Shiny,
+		Ability:  wild.Ability,
+		HeldItem: wild.HeldItem,
+	}
+	// a wild pokemon hasn't battled yet, so no effort values
+	for _, stat := range stats.Names {
+		snapshot.EVs[stat] = 0
+	}
+	setSpecies(&snapshot, pokemon)
+	return snapshot
+}
+
+// setSpecies fills in what every pokemon of a species has in common, which is everything
+// that changes when it evolves
+func setSpecies(snapshot *savefile.Pokemon, pokemon pokeapi.PokeAPIPokemonResponse) {
+	// the species' ID is its national dex number, forms have pokemon IDs of their own from
+	// 10001 on. Without a link it's 0 and the pokedex looks the number up when it's needed
+	snapshot.SpeciesID, _ = pokeapi.ResourceID(pokemon.Species.URL)
+	snapshot.Name = pokemon.Name
+	snapshot.Height = pokemon.Height
+	snapshot.Weight = pokemon.Weight
+	snapshot.Sprite = pokemon.Sprites.FrontDefault
+	if snapshot.Shiny && pokemon.Sprites.FrontShiny != "" {
+		snapshot.Sprite = pokemon.Sprites.FrontShiny
+	}
+	snapshot.Stats = nil
+	for _, stat := range pokemon.Stats {
+		snapshot.Stats = append(snapshot.Stats, savefile.Stat{Name: stat.Stat.Name, BaseStat: stat.BaseStat})
+	}
+	snapshot.Types = nil
+	for _, pokemonType := range pokemon.Types {
+		snapshot.Types = append(snapshot.Types, pokemonType.Type.Name)
+	}
+}
+
+func commandSave(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, err
+	}
+	return gameResult{Action: "saved", Path: config.savePath, Pokemon: config.save.Count()}, nil
+}
+
+func commandLoad(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	save, err := savefile.Load(cmd.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	switchGame(config, save, cmd.Args[0])
+	return gameResult{Action: "loaded", Path: cmd.Args[0], Pokemon: save.Count()}, nil
+}
+
+func commandNewGame(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	switchGame(config, savefile.New(), config.savePath)
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, err
+	}
+	return gameResult{Action: "new", Path: config.savePath}, nil
+}
+
+// switchGame plays save from now on. Nothing that was going on in the old game, a wild
+// pokemon, a battle or the page map was on, carries over
+func switchGame(config *config, save *savefile.Save, path string) {
+	config.save, config.savePath = save, path
+	config.wild, config.battle = nil, nil
+	config.locationPage, config.lastLocationPage = -1, false
+}
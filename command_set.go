@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/output"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+)
+
+// commandSet changes a setting for the rest of the session, e.g. set output json. The
+// game version is kept in the save
+func commandSet(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	setting, value := cmd.Args[0], cmd.Args[1]
+	switch setting {
+	case "output":
+		if _, ok := output.Lookup(value); !ok {
+			return nil, fmt.Errorf("unknown output format '%s', pick one of %s", value, strings.Join(output.Names(), ", "))
+		}
+		config.output = value
+	case "language":
+		// anything goes, texts the PokeAPI doesn't have in the language are shown in English
+		config.language = value
+	case "version":
+		// "any" goes back to mixing every game
+		if value == "any" {
+			value = ""
+		} else {
+			group, err := config.client.GetVersionGroup(ctx, value)
+			if err != nil {
+				return nil, apiError(err, "version group", value)
+			}
+			config.versionGroup = &group
+		}
+		config.save.GameVersion = value
+		if err := config.save.Write(config.savePath); err != nil {
+			return nil, fmt.Errorf("couldn't save the version: %w", err)
+		}
+		if value == "" {
+			value = "any"
+		}
+	default:
+		return nil, fmt.Errorf("unknown setting '%s'", setting)
+	}
+	return settingResult{Setting: setting, Value: value}, nil
+}
+
+// gameVersion is the version group picked with set version, nil when playing across every
+// game. It's fetched the first time it's needed and then kept for the session
+func (c *config) gameVersion(ctx context.Context) (*pokeapi.PokeAPIVersionGroupResponse, error) {
+	name := c.save.GameVersion
+	if name == "" {
+		return nil, nil
+	}
+	if c.versionGroup == nil || c.versionGroup.Name != name {
+		group, err := c.client.GetVersionGroup(ctx, name)
+		if err != nil {
+			return nil, apiError(err, "version group", name)
+		}
+		c.versionGroup = &group
+	}
+	return c.versionGroup, nil
+}
+
+// versionNames lists the games of a version group, e.g. red and blue for red-blue
+func versionNames(group *pokeapi.PokeAPIVersionGroupResponse) []string {
+	return namesOf(group.Versions)
+}
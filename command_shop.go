@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+)
+
+// martItems is what the Poké Mart sells, the prices come from the PokeAPI
+var martItems = []string{
+	"poke-ball", "great-ball", "ultra-ball",
+	"potion", "super-potion", "hyper-potion", "revive", "ether",
+	"oran-berry", "water-stone", "fire-stone", "thunder-stone",
+}
+
+// maxQuantity is the most of an item that can be bought or sold at once
+const maxQuantity = 99
+
+// commandShop lists what the Poké Mart sells and for how much
+func commandShop(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	result := shopResult{Money: config.save.Money, Items: []shopItem{}}
+	for _, name := range martItems {
+		item, err := config.client.GetItem(ctx, name)
+		if err != nil {
+			return nil, apiError(err, "item", name)
+		}
+		result.Items = append(result.Items, shopItem{Name: item.Name, Price: item.Cost, InBag: config.save.ItemCount(item.Name)})
+	}
+	return result, nil
+}
+
+// commandBuy buys an item from the Poké Mart, one unless a quantity is given
+func commandBuy(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	item, quantity, err := tradedItem(ctx, config, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(martItems, item.Name) || item.Cost == 0 {
+		return nil, fmt.Errorf("the Poké Mart doesn't sell %s, use shop to see what it does", item.Name)
+	}
+	// the pocket is looked up before paying, so a failed lookup doesn't cost anything
+	pocket, err := itemPocket(ctx, config, item)
+	if err != nil {
+		return nil, err
+	}
+	total := item.Cost * quantity
+	if err := config.save.Spend(total); errors.Is(err, savefile.ErrNotEnoughMoney) {
+		return nil, fmt.Errorf("%w: %d %s cost %s and you have %s", err, quantity, item.Name, formatMoney(total), formatMoney(config.save.Money))
+	} else if err != nil {
+		return nil, err
+	}
+	if err := config.save.AddItem(item.Name, pocket, quantity); err != nil {
+		config.save.Earn(total)
+		return nil, err
+	}
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, fmt.Errorf("couldn't save the bag: %w", err)
+	}
+	return tradeResult{Action: "bought", Item: item.Name, Quantity: quantity, Total: total, Money: config.save.Money}, nil
+}
+
+// commandSell sells items from the bag for half what they cost. Key items and items without
+// a price can't be sold
+func commandSell(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	item, quantity, err := tradedItem(ctx, config, cmd)
+	if err != nil {
+		return nil, err
+	}
+	pocket, err := itemPocket(ctx, config, item)
+	if err != nil {
+		return nil, err
+	}
+	if item.Cost == 0 || pocket == savefile.PocketKeyItems {
+		return nil, fmt.Errorf("%s can't be sold", item.Name)
+	}
+	// money past MaxMoney would be lost, so nothing is sold that can't be paid for in full
+	payout := item.Cost / 2 * quantity
+	if config.save.Money+payout > savefile.MaxMoney {
+		return nil, fmt.Errorf("you can't carry %s more, you already have %s", formatMoney(payout), formatMoney(config.save.Money))
+	}
+	if err := config.save.RemoveItem(item.Name, quantity); errors.Is(err, savefile.ErrNoItem) {
+		return nil, fmt.Errorf("you only have %d %s", config.save.ItemCount(item.Name), item.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	earned := config.save.Earn(payout)
+	if err := config.save.Write(config.savePath); err != nil {
+		return nil, fmt.Errorf("couldn't save the bag: %w", err)
+	}
+	return tradeResult{Action: "sold", Item: item.Name, Quantity: quantity, Total: earned, Money: config.save.Money}, nil
+}
+
+// tradedItem is the item and quantity to buy or sell, from <item> [quantity]
+func tradedItem(ctx context.Context, config *config, cmd cmdline.Command) (pokeapi.PokeAPIItemResponse, int, error) {
+	quantity := 1
+	if len(cmd.Args) > 1 {
+		var err error
+		quantity, err = strconv.Atoi(cmd.Args[1])
+		if err != nil || quantity < 1 || quantity > maxQuantity {
+			return pokeapi.PokeAPIItemResponse{}, 0, fmt.Errorf("'%s' isn't a quantity, pick one from 1 to %d", cmd.Args[1], maxQuantity)
+		}
+	}
+	itemName := strings.ToLower(cmd.Args[0])
+	item, err := config.client.GetItem(ctx, itemName)
+	if err != nil {
+		return pokeapi.PokeAPIItemResponse{}, 0, apiError(err, "item", itemName)
+	}
+	return item, quantity, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+)
+
+// errNowhere is returned by the commands that need the player to be somewhere
+var errNowhere = errors.New("you haven't traveled anywhere yet, use travel <location>")
+
+// commandTravel moves the player to a location, into its first area unless --area picks another
+func commandTravel(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	locationName := cmd.Args[0]
+	location, err := config.client.GetLocation(ctx, locationName)
+	if err != nil {
+		return nil, apiError(err, "location", locationName)
+	}
+
+	areas := namesOf(location.Areas)
+	position := savefile.Position{Region: location.Region.Name, Location: location.Name}
+	if areaName, ok := cmd.Flags["area"]; ok {
+		if !slices.Contains(areas, areaName) {
+			return nil, fmt.Errorf("%s has no area named '%s', use areas to list them", location.Name, areaName)
+		}
+		position.Area = areaName
+	} else if len(areas) > 0 {
+		// some locations have no areas at all, there's just nothing to explore there
+		position.Area = areas[0]
+	}
+	if err := config.moveTo(position); err != nil {
+		return nil, err
+	}
+	return travelResult{Position: position, Areas: areas}, nil
+}
+
+// commandAreas lists the areas of the location the player is in
+func commandAreas(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	position := config.save.Position
+	if position.Location == "" {
+		return nil, errNowhere
+	}
+	location, err := config.client.GetLocation(ctx, position.Location)
+	if err != nil {
+		return nil, apiError(err, "location", position.Location)
+	}
+	return areasResult{Position: position, Areas: namesOf(location.Areas)}, nil
+}
+
+// commandRegion lists the locations of a region, the one the player is in by default
+func commandRegion(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	regionName := config.save.Position.Region
+	if len(cmd.Args) > 0 {
+		regionName = cmd.Args[0]
+	}
+	if regionName == "" {
+		return nil, errNowhere
+	}
+	region, err := config.client.GetRegion(ctx, regionName)
+	if err != nil {
+		return nil, apiError(err, "region", regionName)
+	}
+	result := regionResult{Region: region.Name, Locations: namesOf(region.Locations)}
+	if region.Name == config.save.Position.Region {
+		result.Current = config.save.Position.Location
+	}
+	return result, nil
+}
+
+// moveTo puts the player at position and saves, whatever wild pokemon was around stays behind
+func (c *config) moveTo(position savefile.Position) error {
+	if position.Area != c.save.Position.Area {
+		c.wild = nil
+	}
+	c.save.Position = position
+	if err := c.save.Write(c.savePath); err != nil {
+		return fmt.Errorf("couldn't save where you are: %w", err)
+	}
+	return nil
+}
+
+// moveToArea moves the player into area, which might be in another location (and region)
+func (c *config) moveToArea(ctx context.Context, area pokeapi.PokeAPILocationAreaResponse) error {
+	position := c.save.Position
+	if area.Location.Name != position.Location {
+		location, err := c.client.GetLocation(ctx, area.Location.Name)
+		if err != nil {
+			return apiError(err, "location", area.Location.Name)
+		}
+		position = savefile.Position{Region: location.Region.Name, Location: location.Name}
+	}
+	position.Area = area.Name
+	return c.moveTo(position)
+}
+
+func namesOf(resources []pokeapi.NamedAPIResource) []string {
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	return names
+}
@@ -0,0 +1,261 @@
+package battle
+
+import (
+	"math"
+	"slices"
+
+	"github.com/staf3333/pokedexcli/internal/rng"
+)
+
+// Effectiveness tells how well a move of the attacking type does against the defending
+// types, *typechart.Chart satisfies it
+type Effectiveness interface {
+	Multiplier(attacking string, defending ...string) float64
+}
+
+// damage classes, status moves don't do damage
+const (
+	Physical = "physical"
+	Special  = "special"
+	Status   = "status"
+)
+
+// Move is what a pokemon can do on its turn
+type Move struct {
+	Name        string
+	Type        string
+	DamageClass string
+	Power       int
+	// Accuracy is the percent chance to hit, 0 for moves that never miss
+	Accuracy int
+	// moves with a higher priority go first whatever the speed of the pokemon
+	Priority int
+	// PP is how many more times the move can be used
+	PP int
+}
+
+// PrizePerLevel is the money won for each level of a defeated wild pokemon
+const PrizePerLevel = 20
+
+// Struggle is used by a pokemon that has no moves, or no PP left for any of them. It has
+// no type, so it is never super effective and never resisted
+var Struggle = Move{Name: "struggle", DamageClass: Physical, Power: 50}
+
+// Pokemon is one side of a battle
+type Pokemon struct {
+	// ID is the save file ID of the player's pokemon, 0 for a wild one
+	ID    int
+	Name  string
+	Types []string
+	Level int
+	// Stats are the actual stats keyed by stat name, "hp" is the maximum HP
+	Stats map[string]int
+	HP    int
+	Moves []Move
+}
+
+func (p *Pokemon) MaxHP() int {
+	return p.Stats["hp"]
+}
+
+func (p *Pokemon) Fainted() bool {
+	return p.HP <= 0
+}
+
+// Move finds one of the pokemon's moves by name
+func (p *Pokemon) Move(name string) (Move, bool) {
+	for _, move := range p.Moves {
+		if move.Name == name {
+			return move, true
+		}
+	}
+	return Move{}, false
+}
+
+// OutOfPP reports whether the pokemon can't use any of its moves anymore, and has to struggle
+func (p *Pokemon) OutOfPP() bool {
+	for _, move := range p.Moves {
+		if move.PP > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// spend uses up one PP of the move, if the pokemon knows it
+func (p *Pokemon) spend(name string) {
+	for i := range p.Moves {
+		if p.Moves[i].Name == name && p.Moves[i].PP > 0 {
+			p.Moves[i].PP--
+			return
+		}
+	}
+}
+
+// Hit is what happened when a pokemon used a move
+type Hit struct {
+	Attacker string
+	Defender string
+	Move     string
+	Missed   bool
+	Damage   int
+	Critical bool
+	// Effectiveness is the type multiplier, 1 for status moves
+	Effectiveness float64
+	// Fainted is set when the defender fainted from this hit
+	Fainted bool
+	// Wild is set when the wild pokemon was the attacker
+	Wild bool
+}
+
+// one in critChance hits is a critical hit
+const critChance = 24
+
+// Attack has attacker use move on defender and takes the damage off the defender's HP
+func Attack(rng rng.Rand, chart Effectiveness, attacker, defender *Pokemon, move Move) Hit {
+	hit := Hit{Attacker: attacker.Name, Defender: defender.Name, Move: move.Name, Effectiveness: 1}
+	attacker.spend(move.Name)
+	if move.Accuracy > 0 && rng.Intn(100) >= move.Accuracy {
+		hit.Missed = true
+		return hit
+	}
+	if move.DamageClass == Status || move.Power == 0 {
+		return hit
+	}
+	if move.Type != "" {
+		hit.Effectiveness = chart.Multiplier(move.Type, defender.Types...)
+	}
+	if hit.Effectiveness == 0 {
+		return hit
+	}
+	hit.Critical = rng.Intn(critChance) == 0
+	// the random factor goes from 85% to 100%
+	roll := 85 + rng.Intn(16)
+	hit.Damage = Damage(attacker, defender, move, hit.Effectiveness, hit.Critical, roll)
+	defender.HP = max(defender.HP-hit.Damage, 0)
+	hit.Fainted = defender.Fainted()
+	return hit
+}
+
+// Damage is the mainline (Gen V onwards) damage formula:
+//
+//	base = (2*level/5 + 2) * power * attack/defense / 50 + 2
+//	damage = base * roll/100 * critical(1.5) * STAB(1.5) * effectiveness
+//
+// rounding down at every step. Special moves use the special stats, and anything that
+// does damage does at least 1
+func Damage(attacker, defender *Pokemon, move Move, effectiveness float64, critical bool, roll int) int {
+	attack, defense := attacker.Stats["attack"], defender.Stats["defense"]
+	if move.DamageClass == Special {
+		attack, defense = attacker.Stats["special-attack"], defender.Stats["special-defense"]
+	}
+	base := (2*attacker.Level/5+2)*move.Power*attack/max(defense, 1)/50 + 2
+
+	// like the games, round down after every multiplier
+	damage := base * roll / 100
+	if critical {
+		damage = damage * 3 / 2
+	}
+	// same type attack bonus
+	if move.Type != "" && slices.Contains(attacker.Types, move.Type) {
+		damage = damage * 3 / 2
+	}
+	damage = int(math.Floor(float64(damage) * effectiveness))
+	return max(damage, 1)
+}
+
+// Battle is the player's pokemon against a wild one
+type Battle struct {
+	Player *Pokemon
+	Wild   *Pokemon
+	rng    rng.Rand
+	chart  Effectiveness
+	// every failed attempt to run makes the next one likelier to work
+	escapeAttempts int
+}
+
+func New(rng rng.Rand, chart Effectiveness, player, wild *Pokemon) *Battle {
+	return &Battle{Player: player, Wild: wild, rng: rng, chart: chart}
+}
+
+// Fight plays a turn where the player's pokemon uses move. Both pokemon attack, the one
+// with the higher priority move (or the faster one) first. A pokemon that faints before its
+// turn doesn't get one
+func (b *Battle) Fight(move Move) []Hit {
+	wildMove := b.wildMove()
+	if b.playerFirst(move, wildMove) {
+		hits := []Hit{Attack(b.rng, b.chart, b.Player, b.Wild, move)}
+		if !b.Wild.Fainted() {
+			hits = append(hits, b.wildAttack(wildMove))
+		}
+		return hits
+	}
+	hits := []Hit{b.wildAttack(wildMove)}
+	if !b.Player.Fainted() {
+		hits = append(hits, Attack(b.rng, b.chart, b.Player, b.Wild, move))
+	}
+	return hits
+}
+
+// WildTurn is the wild pokemon attacking on its own, after the player used their turn to
+// switch, throw a ball or (unsuccessfully) run
+func (b *Battle) WildTurn() Hit {
+	return b.wildAttack(b.wildMove())
+}
+
+// Switch sends out another of the player's pokemon
+func (b *Battle) Switch(p *Pokemon) {
+	b.Player = p
+}
+
+// Run tries to get away using the Gen III escape formula: always when the player's pokemon
+// is faster, otherwise with odds that grow with its speed and the number of attempts
+func (b *Battle) Run() bool {
+	b.escapeAttempts++
+	speed, wildSpeed := b.Player.Stats["speed"], b.Wild.Stats["speed"]
+	if speed >= wildSpeed {
+		return true
+	}
+	odds := speed*128/max(wildSpeed, 1) + 30*b.escapeAttempts
+	if odds > 255 {
+		return true
+	}
+	return b.rng.Intn(256) < odds
+}
+
+// Prize is the money the player wins for defeating the wild pokemon
+func (b *Battle) Prize() int {
+	return PrizePerLevel * b.Wild.Level
+}
+
+func (b *Battle) wildAttack(move Move) Hit {
+	hit := Attack(b.rng, b.chart, b.Wild, b.Player, move)
+	hit.Wild = true
+	return hit
+}
+
+// wildMove picks one of the wild pokemon's moves that still has PP
+func (b *Battle) wildMove() Move {
+	var usable []Move
+	for _, move := range b.Wild.Moves {
+		if move.PP > 0 {
+			usable = append(usable, move)
+		}
+	}
+	if len(usable) == 0 {
+		return Struggle
+	}
+	return usable[b.rng.Intn(len(usable))]
+}
+
+func (b *Battle) playerFirst(move, wildMove Move) bool {
+	if move.Priority != wildMove.Priority {
+		return move.Priority > wildMove.Priority
+	}
+	speed, wildSpeed := b.Player.Stats["speed"], b.Wild.Stats["speed"]
+	if speed != wildSpeed {
+		return speed > wildSpeed
+	}
+	// a speed tie is a coin flip
+	return b.rng.Intn(2) == 0
+}
@@ -0,0 +1,164 @@
+package battle
+
+import (
+	"testing"
+
+	"github.com/staf3333/pokedexcli/internal/randtest"
+	"github.com/staf3333/pokedexcli/internal/typechart"
+)
+
+func newTestChart() typechart.Chart {
+	chart := typechart.Chart{}
+	chart.Add("ice", typechart.Relations{DoubleDamageTo: []string{"dragon", "ground"}})
+	chart.Add("electric", typechart.Relations{DoubleDamageTo: []string{"water"}, NoDamageTo: []string{"ground"}})
+	return chart
+}
+
+// the worked example of the damage formula: a level 75 glaceon using ice fang on a garchomp
+func newGlaceonAndGarchomp() (*Pokemon, *Pokemon) {
+	glaceon := &Pokemon{Name: "glaceon", Types: []string{"ice"}, Level: 75, Stats: map[string]int{"attack": 123, "hp": 200, "speed": 80}, HP: 200}
+	garchomp := &Pokemon{Name: "garchomp", Types: []string{"dragon", "ground"}, Level: 75, Stats: map[string]int{"defense": 163, "hp": 250, "speed": 120}, HP: 250}
+	return glaceon, garchomp
+}
+
+var iceFang = Move{Name: "ice-fang", Type: "ice", DamageClass: Physical, Power: 65, Accuracy: 95}
+
+func TestDamage(t *testing.T) {
+	glaceon, garchomp := newGlaceonAndGarchomp()
+	if damage := Damage(glaceon, garchomp, iceFang, 4, false, 85); damage != 168 {
+		t.Errorf("expected the lowest roll to do 168, got %d", damage)
+	}
+	if damage := Damage(glaceon, garchomp, iceFang, 4, false, 100); damage != 196 {
+		t.Errorf("expected the highest roll to do 196, got %d", damage)
+	}
+	if damage := Damage(glaceon, garchomp, iceFang, 4, true, 100); damage != 292 {
+		t.Errorf("expected a critical hit to do 292, got %d", damage)
+	}
+}
+
+func TestAttack(t *testing.T) {
+	cases := []struct {
+		name     string
+		move     Move
+		rolls    []int
+		expected Hit
+	}{
+		{
+			name:     "hit",
+			move:     iceFang,
+			rolls:    []int{0, 1, 15},
+			expected: Hit{Attacker: "glaceon", Defender: "garchomp", Move: "ice-fang", Damage: 196, Effectiveness: 4},
+		},
+		{
+			name:     "critical hit knocks out",
+			move:     iceFang,
+			rolls:    []int{0, 0, 15},
+			expected: Hit{Attacker: "glaceon", Defender: "garchomp", Move: "ice-fang", Damage: 292, Critical: true, Effectiveness: 4, Fainted: true},
+		},
+		{
+			name:     "miss",
+			move:     iceFang,
+			rolls:    []int{95},
+			expected: Hit{Attacker: "glaceon", Defender: "garchomp", Move: "ice-fang", Missed: true, Effectiveness: 1},
+		},
+		{
+			name:     "no effect",
+			move:     Move{Name: "thunderbolt", Type: "electric", DamageClass: Special, Power: 90, Accuracy: 100},
+			rolls:    []int{0},
+			expected: Hit{Attacker: "glaceon", Defender: "garchomp", Move: "thunderbolt", Effectiveness: 0},
+		},
+		{
+			name:     "status move",
+			move:     Move{Name: "growl", Type: "normal", DamageClass: Status},
+			expected: Hit{Attacker: "glaceon", Defender: "garchomp", Move: "growl", Effectiveness: 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			glaceon, garchomp := newGlaceonAndGarchomp()
+			actual := Attack(&randtest.Rand{Rolls: c.rolls}, newTestChart(), glaceon, garchomp, c.move)
+			if actual != c.expected {
+				t.Errorf("expected %+v, got %+v", c.expected, actual)
+			}
+			if garchomp.HP != max(250-c.expected.Damage, 0) {
+				t.Errorf("expected garchomp to lose %d HP, has %d left", c.expected.Damage, garchomp.HP)
+			}
+		})
+	}
+}
+
+func TestFightOrder(t *testing.T) {
+	glaceon, garchomp := newGlaceonAndGarchomp()
+	garchomp.Moves = []Move{{Name: "tackle", Type: "normal", DamageClass: Physical, Power: 40, PP: 35}}
+	b := New(&randtest.Rand{Rolls: []int{0, 1, 0, 0, 1, 0}}, newTestChart(), glaceon, garchomp)
+	hits := b.Fight(iceFang)
+	if len(hits) != 2 || hits[0].Attacker != "garchomp" || hits[1].Attacker != "glaceon" {
+		t.Errorf("expected the faster garchomp to go first, got %+v", hits)
+	}
+	if !hits[0].Wild || hits[1].Wild {
+		t.Errorf("expected only garchomp's hit to be marked wild, got %+v", hits)
+	}
+
+	quickAttack := Move{Name: "quick-attack", Type: "normal", DamageClass: Physical, Power: 40, Priority: 1}
+	b = New(&randtest.Rand{Rolls: []int{0, 1, 0, 1, 0}}, newTestChart(), glaceon, garchomp)
+	if hits := b.Fight(quickAttack); hits[0].Attacker != "glaceon" {
+		t.Errorf("expected the priority move to go first, got %+v", hits)
+	}
+}
+
+func TestMovesUsePP(t *testing.T) {
+	glaceon, garchomp := newGlaceonAndGarchomp()
+	glaceon.Moves = []Move{{Name: "ice-fang", Type: "ice", DamageClass: Physical, Power: 65, PP: 1}}
+	garchomp.Moves = []Move{{Name: "tackle", Type: "normal", DamageClass: Physical, Power: 40}}
+	// garchomp has no PP left for tackle so it struggles, which doesn't take a roll to pick
+	b := New(&randtest.Rand{Rolls: []int{1, 0, 1, 0, 1, 0}}, newTestChart(), glaceon, garchomp)
+	hits := b.Fight(glaceon.Moves[0])
+	if hits[0].Move != "struggle" {
+		t.Errorf("expected garchomp to struggle, got %+v", hits)
+	}
+	if !glaceon.OutOfPP() || glaceon.Moves[0].PP != 0 {
+		t.Errorf("expected ice-fang's last PP to be used, got %+v", glaceon.Moves)
+	}
+}
+
+func TestFaintedPokemonDoesntMove(t *testing.T) {
+	glaceon, garchomp := newGlaceonAndGarchomp()
+	garchomp.Stats["speed"] = 10
+	garchomp.HP = 1
+	b := New(&randtest.Rand{Rolls: []int{0, 0, 1, 0}}, newTestChart(), glaceon, garchomp)
+	if hits := b.Fight(iceFang); len(hits) != 1 || !hits[0].Fainted {
+		t.Errorf("expected garchomp to faint before its turn, got %+v", hits)
+	}
+}
+
+func TestRun(t *testing.T) {
+	glaceon, garchomp := newGlaceonAndGarchomp()
+	// 80*128/120 + 30 = 115
+	b := New(&randtest.Rand{Rolls: []int{115, 114}}, newTestChart(), glaceon, garchomp)
+	if b.Run() {
+		t.Errorf("expected a roll of 115 to fail")
+	}
+	if !b.Run() {
+		t.Errorf("expected the second attempt to get away")
+	}
+
+	// the odds go up by 30 an attempt, 115 to 235 never beats a roll of 255 but from the
+	// sixth attempt on they're past 255 and running always works
+	b = New(&randtest.Rand{Rolls: []int{255, 255, 255, 255, 255}}, newTestChart(), glaceon, garchomp)
+	for attempt := 1; attempt <= 5; attempt++ {
+		if b.Run() {
+			t.Errorf("expected attempt %d to fail with a roll of 255", attempt)
+		}
+	}
+	for attempt := 6; attempt <= 8; attempt++ {
+		if !b.Run() {
+			t.Errorf("expected attempt %d to get away", attempt)
+		}
+	}
+
+	garchomp.Stats["speed"] = 10
+	if !New(&randtest.Rand{}, newTestChart(), glaceon, garchomp).Run() {
+		t.Errorf("expected the faster pokemon to always get away")
+	}
+}
@@ -0,0 +1,138 @@
+package catch
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/rng"
+)
+
+// Ball is a kind of Poké Ball and its catch rate modifier
+type Ball struct {
+	Name     string
+	Modifier float64
+	// the Master Ball skips the formula entirely
+	AlwaysCatches bool
+}
+
+var balls = map[string]Ball{
+	"poke-ball":   {Name: "poke-ball", Modifier: 1},
+	"great-ball":  {Name: "great-ball", Modifier: 1.5},
+	"ultra-ball":  {Name: "ultra-ball", Modifier: 2},
+	"master-ball": {Name: "master-ball", Modifier: 255, AlwaysCatches: true},
+}
+
+// DefaultBall is what gets thrown when nothing else is asked for
+const DefaultBall = "poke-ball"
+
+// LookupBall finds a ball by its PokeAPI item name ("great-ball"). "great", "Great Ball" and
+// "greatball" work too since that's how people tend to type them
+func LookupBall(name string) (Ball, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.NewReplacer(" ", "-", "_", "-").Replace(key)
+	key = strings.TrimSuffix(strings.TrimSuffix(key, "-ball"), "ball")
+	if ball, ok := balls[key+"-ball"]; ok {
+		return ball, nil
+	}
+	return Ball{}, fmt.Errorf("unknown ball '%s', pick one of %s", name, strings.Join(BallNames(), ", "))
+}
+
+// BallNames lists the balls in alphabetical order
+func BallNames() []string {
+	names := make([]string, 0, len(balls))
+	for name := range balls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Status is a major status condition, sleeping or frozen pokemon are the easiest to catch
+type Status string
+
+const (
+	StatusNone      Status = ""
+	StatusSleep     Status = "sleep"
+	StatusFreeze    Status = "freeze"
+	StatusParalysis Status = "paralysis"
+	StatusPoison    Status = "poison"
+	StatusBurn      Status = "burn"
+)
+
+func (s Status) modifier() float64 {
+	switch s {
+	case StatusSleep, StatusFreeze:
+		return 2
+	case StatusParalysis, StatusPoison, StatusBurn:
+		return 1.5
+	}
+	return 1
+}
+
+// Target is the wild pokemon a ball is thrown at
+type Target struct {
+	// CaptureRate comes from the species, 3 for most legendaries up to 255 for e.g. caterpie
+	CaptureRate int
+	MaxHP       int
+	HP          int
+	Status      Status
+}
+
+// Result of one throw. Shakes is how many times the ball wobbled (0-3) before the pokemon
+// broke free, or 3 when it was caught
+type Result struct {
+	Caught bool
+	Shakes int
+}
+
+// the game makes four shake checks, passing all four is a catch. Only three shakes are shown,
+// the fourth check is the "click"
+const shakeChecks = 4
+
+// Attempt throws ball at target using the Gen III/IV capture formula:
+//
+//	a = (3*MaxHP - 2*HP) * CaptureRate * ball / (3*MaxHP) * status
+//
+// a >= 255 is a guaranteed catch, otherwise each shake check passes with probability b/65536
+// where b = 1048560 / sqrt(sqrt(16711680 / a))
+func Attempt(rng rng.Rand, target Target, ball Ball) Result {
+	if ball.AlwaysCatches {
+		return Result{Caught: true, Shakes: shakeChecks - 1}
+	}
+	a := CatchValue(target, ball)
+	if a >= 255 {
+		return Result{Caught: true, Shakes: shakeChecks - 1}
+	}
+
+	b := ShakeProbability(a)
+	passed := 0
+	for passed < shakeChecks && rng.Intn(65536) < b {
+		passed++
+	}
+	if passed == shakeChecks {
+		return Result{Caught: true, Shakes: shakeChecks - 1}
+	}
+	return Result{Caught: false, Shakes: min(passed, shakeChecks-1)}
+}
+
+// CatchValue is the "a" value of the capture formula
+func CatchValue(target Target, ball Ball) float64 {
+	// outside of a battle a wild pokemon is at full health
+	maxHP, hp := max(target.MaxHP, 1), target.HP
+	if target.MaxHP <= 0 {
+		hp = maxHP
+	}
+	hp = max(min(hp, maxHP), 1)
+	a := float64(3*maxHP-2*hp) * float64(target.CaptureRate) * ball.Modifier / float64(3*maxHP)
+	return max(a*target.Status.modifier(), 1)
+}
+
+// ShakeProbability is the "b" value of the capture formula, out of 65536
+func ShakeProbability(a float64) int {
+	if a >= 255 {
+		return 65536
+	}
+	return int(1048560 / math.Sqrt(math.Sqrt(16711680/a)))
+}
@@ -0,0 +1,115 @@
+package catch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/staf3333/pokedexcli/internal/randtest"
+)
+
+func TestCatchValue(t *testing.T) {
+	pokeBall, _ := LookupBall("poke-ball")
+	ultraBall, _ := LookupBall("ultra-ball")
+	cases := []struct {
+		name     string
+		target   Target
+		ball     Ball
+		expected float64
+	}{
+		{
+			name:     "full hp",
+			target:   Target{CaptureRate: 45, MaxHP: 30, HP: 30},
+			ball:     pokeBall,
+			expected: 15,
+		},
+		{
+			name:     "1 hp",
+			target:   Target{CaptureRate: 45, MaxHP: 30, HP: 1},
+			ball:     pokeBall,
+			expected: 44,
+		},
+		{
+			name:     "asleep in an ultra ball",
+			target:   Target{CaptureRate: 45, MaxHP: 30, HP: 30, Status: StatusSleep},
+			ball:     ultraBall,
+			expected: 60,
+		},
+		{
+			name:     "no hp given means full hp",
+			target:   Target{CaptureRate: 45},
+			ball:     pokeBall,
+			expected: 15,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := CatchValue(c.target, c.ball); actual != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestShakeProbability(t *testing.T) {
+	if b := ShakeProbability(255); b != 65536 {
+		t.Errorf("expected a=255 to always pass, got %d", b)
+	}
+	if b := ShakeProbability(1); b != 16399 {
+		t.Errorf("expected b=16399 for a=1, got %d", b)
+	}
+	if low, high := ShakeProbability(10), ShakeProbability(100); low >= high {
+		t.Errorf("expected b to grow with a, got %d and %d", low, high)
+	}
+}
+
+func TestAttempt(t *testing.T) {
+	pokeBall, _ := LookupBall("poke-ball")
+	target := Target{CaptureRate: 45}
+	b := ShakeProbability(CatchValue(target, pokeBall))
+
+	cases := []struct {
+		name     string
+		rolls    []int
+		expected Result
+	}{
+		{name: "all checks pass", rolls: []int{0, 0, 0, 0}, expected: Result{Caught: true, Shakes: 3}},
+		{name: "breaks free immediately", rolls: []int{b}, expected: Result{Shakes: 0}},
+		{name: "breaks free after two shakes", rolls: []int{0, b - 1, b + 1}, expected: Result{Shakes: 2}},
+		{name: "fails the last check", rolls: []int{0, 0, 0, 65535}, expected: Result{Shakes: 3}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := Attempt(&randtest.Rand{Rolls: c.rolls}, target, pokeBall)
+			if actual != c.expected {
+				t.Errorf("expected %+v, got %+v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMasterBallAlwaysCatches(t *testing.T) {
+	masterBall, err := LookupBall("Master Ball")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if !Attempt(rng, Target{CaptureRate: 3}, masterBall).Caught {
+			t.Fatalf("expected the master ball to always catch")
+		}
+	}
+}
+
+func TestLookupBall(t *testing.T) {
+	for _, name := range []string{"great", "great-ball", "Great Ball", "greatball"} {
+		ball, err := LookupBall(name)
+		if err != nil || ball.Name != "great-ball" {
+			t.Errorf("expected %q to be the great-ball, got %+v (%v)", name, ball, err)
+		}
+	}
+	if _, err := LookupBall("heavy-ball"); err == nil {
+		t.Errorf("expected an error for an unknown ball")
+	}
+}
@@ -0,0 +1,160 @@
+package cmdline
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Command is one parsed line of input: the command name, its positional arguments and any
+// --flag=value options. A bare --flag is stored with the value "true"
+type Command struct {
+	Name  string
+	Args  []string
+	Flags map[string]string
+}
+
+// Bool reports whether a switch like --json was given (and not as --json=false)
+func (c Command) Bool(name string) bool {
+	value, err := strconv.ParseBool(c.Flags[name])
+	return err == nil && value
+}
+
+var (
+	ErrUnterminatedQuote = errors.New("unterminated quote")
+	ErrTrailingEscape    = errors.New("trailing backslash")
+	ErrMissingValue      = errors.New("missing flag value")
+)
+
+// SplitCommands tokenizes a line that may hold several commands separated by an unquoted ;
+// e.g. `catch pikachu; inspect pikachu`, giving the tokens of each. Empty commands are skipped
+func SplitCommands(input string) ([][]string, error) {
+	groups, err := tokenize(input, true)
+	if err != nil {
+		return nil, err
+	}
+	commands := [][]string{}
+	for _, tokens := range groups {
+		if len(tokens) > 0 {
+			commands = append(commands, tokens)
+		}
+	}
+	return commands, nil
+}
+
+// FromTokens builds a Command from already split tokens, like the ones from SplitCommands
+// or os.Args. Tokens starting with -- are flags, until a lone -- which makes everything after
+// it an argument. Flags listed in valueFlags take a value, either as --page=3 or --page 3,
+// any other flag given without =value is a switch and gets the value "true"
+func FromTokens(tokens []string, valueFlags map[string]bool) (Command, error) {
+	cmd := Command{Flags: map[string]string{}}
+	if len(tokens) == 0 {
+		return cmd, nil
+	}
+	cmd.Name = tokens[0]
+	flagsDone := false
+	for i := 1; i < len(tokens); i++ {
+		token := tokens[i]
+		if !flagsDone && token == "--" {
+			flagsDone = true
+			continue
+		}
+		if flagsDone || !strings.HasPrefix(token, "--") {
+			cmd.Args = append(cmd.Args, token)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(token, "--"), "=")
+		switch {
+		case hasValue:
+		case valueFlags[name]:
+			if i+1 >= len(tokens) {
+				return Command{}, fmt.Errorf("%w: --%s", ErrMissingValue, name)
+			}
+			i++
+			value = tokens[i]
+		default:
+			value = "true"
+		}
+		cmd.Flags[name] = value
+	}
+	return cmd, nil
+}
+
+// Tokenize splits a line into words the way a shell would (roughly):
+//   - any run of whitespace separates words
+//   - 'single quotes' keep everything inside literally
+//   - "double quotes" keep whitespace, and a backslash escapes the next character
+//   - outside quotes a backslash escapes the next character too
+//
+// so `catch "mr. mime"` and `catch mr.\ mime` both give ["catch", "mr. mime"]
+func Tokenize(input string) ([]string, error) {
+	groups, err := tokenize(input, false)
+	if err != nil {
+		return nil, err
+	}
+	return groups[0], nil
+}
+
+// tokenize does the actual work for Tokenize and SplitCommands. When splitCommands is set an
+// unquoted ; ends the current command and starts a new group of tokens
+func tokenize(input string, splitCommands bool) ([][]string, error) {
+	groups := [][]string{}
+	tokens := []string{}
+	var current strings.Builder
+	// inToken tracks whether we've started a word, so "" still counts as an (empty) argument
+	inToken := false
+	var quote rune
+	escaped := false
+
+	for _, r := range input {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inToken = true
+		case unicode.IsSpace(r) || (splitCommands && r == ';'):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+			if r == ';' {
+				groups = append(groups, tokens)
+				tokens = []string{}
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if escaped {
+		return nil, ErrTrailingEscape
+	}
+	if quote != 0 {
+		return nil, ErrUnterminatedQuote
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return append(groups, tokens), nil
+}
@@ -0,0 +1,149 @@
+package cmdline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "", expected: []string{}},
+		{input: "   ", expected: []string{}},
+		{input: "catch  pikachu", expected: []string{"catch", "pikachu"}},
+		{input: "  explore a b  ", expected: []string{"explore", "a", "b"}},
+		{input: "catch\tpikachu\n", expected: []string{"catch", "pikachu"}},
+		{input: `catch "mr. mime"`, expected: []string{"catch", "mr. mime"}},
+		{input: `catch 'mr. mime'`, expected: []string{"catch", "mr. mime"}},
+		{input: `catch mr.\ mime`, expected: []string{"catch", "mr. mime"}},
+		{input: `say "a \"quoted\" word"`, expected: []string{"say", `a "quoted" word`}},
+		{input: `say 'no \escapes'`, expected: []string{"say", `no \escapes`}},
+		{input: `say "" x`, expected: []string{"say", "", "x"}},
+		{input: `say ab"c d"e`, expected: []string{"say", "abc de"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			actual, err := Tokenize(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected error
+	}{
+		{input: `catch "pikachu`, expected: ErrUnterminatedQuote},
+		{input: `catch 'pikachu`, expected: ErrUnterminatedQuote},
+		{input: `catch pikachu\`, expected: ErrTrailingEscape},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			_, err := Tokenize(c.input)
+			if !errors.Is(err, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestFromTokens(t *testing.T) {
+	valueFlags := map[string]bool{"page": true, "method": true}
+	cases := []struct {
+		input    string
+		expected Command
+	}{
+		{
+			input:    "help",
+			expected: Command{Name: "help", Flags: map[string]string{}},
+		},
+		{
+			input: "map --page=3 --json",
+			expected: Command{
+				Name:  "map",
+				Flags: map[string]string{"page": "3", "json": "true"},
+			},
+		},
+		{
+			input: "map --page 3 --json",
+			expected: Command{
+				Name:  "map",
+				Flags: map[string]string{"page": "3", "json": "true"},
+			},
+		},
+		{
+			input: `explore "canalave city" --method surf`,
+			expected: Command{
+				Name:  "explore",
+				Args:  []string{"canalave city"},
+				Flags: map[string]string{"method": "surf"},
+			},
+		},
+		{
+			input: "catch -- --weird-name",
+			expected: Command{
+				Name:  "catch",
+				Args:  []string{"--weird-name"},
+				Flags: map[string]string{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			tokens, err := Tokenize(c.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual, err := FromTokens(tokens, valueFlags)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected %+v, got %+v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFromTokensMissingValue(t *testing.T) {
+	_, err := FromTokens([]string{"map", "--page"}, map[string]bool{"page": true})
+	if !errors.Is(err, ErrMissingValue) {
+		t.Errorf("expected %v, got %v", ErrMissingValue, err)
+	}
+}
+
+func TestSplitCommands(t *testing.T) {
+	actual, err := SplitCommands(`catch pikachu; inspect "a;b";; pokedex;`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"catch", "pikachu"},
+		{"inspect", "a;b"},
+		{"pokedex"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	// without SplitCommands a ; is just part of a word
+	tokens, err := Tokenize("catch pikachu;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tokens, []string{"catch", "pikachu;"}) {
+		t.Errorf("unexpected tokens %q", tokens)
+	}
+}
@@ -0,0 +1,132 @@
+package encounter
+
+import (
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/rng"
+)
+
+// ErrNoEncounters is returned when there is nothing to roll, e.g. no fishing in a cave
+var ErrNoEncounters = errors.New("no wild pokemon can be found this way here")
+
+// DefaultMethod is how you look for pokemon unless you ask for something else
+const DefaultMethod = "walk"
+
+// Slot is one entry of an area's encounter table: in Version, using Method, Pokemon shows
+// up Chance percent of the time at a level between MinLevel and MaxLevel
+type Slot struct {
+	Pokemon  string
+	Version  string
+	Method   string
+	Chance   int
+	MinLevel int
+	MaxLevel int
+}
+
+// Encounter is a wild pokemon that appeared
+type Encounter struct {
+	Pokemon string `json:"pokemon"`
+	Level   int    `json:"level"`
+	Version string `json:"version"`
+	Method  string `json:"method"`
+}
+
+// Slots flattens the encounter table of a location area, in the order the PokeAPI lists it
+func Slots(area pokeapi.PokeAPILocationAreaResponse) []Slot {
+	var slots []Slot
+	for _, encounter := range area.PokemonEncounters {
+		for _, version := range encounter.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				slots = append(slots, Slot{
+					Pokemon:  encounter.Pokemon.Name,
+					Version:  version.Version.Name,
+					Method:   detail.Method.Name,
+					Chance:   detail.Chance,
+					MinLevel: detail.MinLevel,
+					MaxLevel: detail.MaxLevel,
+				})
+			}
+		}
+	}
+	return slots
+}
+
+// Filter keeps the slots for version and method, an empty string matches anything
+func Filter(slots []Slot, version, method string) []Slot {
+	var filtered []Slot
+	for _, slot := range slots {
+		if (version == "" || slot.Version == version) && (method == "" || slot.Method == method) {
+			filtered = append(filtered, slot)
+		}
+	}
+	return filtered
+}
+
+// InVersions keeps the slots of any of versions, e.g. the versions of a version group
+func InVersions(slots []Slot, versions []string) []Slot {
+	var filtered []Slot
+	for _, slot := range slots {
+		if slices.Contains(versions, slot.Version) {
+			filtered = append(filtered, slot)
+		}
+	}
+	return filtered
+}
+
+// Versions lists the game versions that have slots, in the order they first appear
+func Versions(slots []Slot) []string {
+	var versions []string
+	seen := map[string]bool{}
+	for _, slot := range slots {
+		if !seen[slot.Version] {
+			seen[slot.Version] = true
+			versions = append(versions, slot.Version)
+		}
+	}
+	return versions
+}
+
+// Methods lists the encounter methods that have slots in alphabetical order
+func Methods(slots []Slot) []string {
+	var methods []string
+	seen := map[string]bool{}
+	for _, slot := range slots {
+		if !seen[slot.Method] {
+			seen[slot.Method] = true
+			methods = append(methods, slot.Method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Roll picks one of slots weighted by its chance, then a level in the slot's range.
+// The chances of a method usually add up to 100 but nothing here relies on that
+func Roll(rng rng.Rand, slots []Slot) (Encounter, error) {
+	total := 0
+	for _, slot := range slots {
+		total += max(slot.Chance, 0)
+	}
+	if total == 0 {
+		return Encounter{}, ErrNoEncounters
+	}
+
+	roll := rng.Intn(total)
+	for _, slot := range slots {
+		chance := max(slot.Chance, 0)
+		if roll >= chance {
+			roll -= chance
+			continue
+		}
+		level := slot.MinLevel
+		if slot.MaxLevel > slot.MinLevel {
+			level += rng.Intn(slot.MaxLevel - slot.MinLevel + 1)
+		}
+		return Encounter{Pokemon: slot.Pokemon, Level: level, Version: slot.Version, Method: slot.Method}, nil
+	}
+	// unreachable, roll is always below total
+	return Encounter{}, ErrNoEncounters
+}
@@ -0,0 +1,87 @@
+package encounter
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/randtest"
+)
+
+var testSlots = []Slot{
+	{Pokemon: "tentacool", Version: "diamond", Method: "surf", Chance: 60, MinLevel: 20, MaxLevel: 30},
+	{Pokemon: "pikachu", Version: "diamond", Method: "walk", Chance: 70, MinLevel: 3, MaxLevel: 5},
+	{Pokemon: "pichu", Version: "diamond", Method: "walk", Chance: 30, MinLevel: 2, MaxLevel: 2},
+	{Pokemon: "pikachu", Version: "pearl", Method: "walk", Chance: 100, MinLevel: 4, MaxLevel: 4},
+}
+
+func TestSlots(t *testing.T) {
+	var area pokeapi.PokeAPILocationAreaResponse
+	data := `{"pokemon_encounters": [{"pokemon": {"name": "pikachu"}, "version_details": [
+		{"version": {"name": "diamond"}, "encounter_details": [
+			{"chance": 70, "min_level": 3, "max_level": 5, "method": {"name": "walk"}}
+		]}
+	]}]}`
+	if err := json.Unmarshal([]byte(data), &area); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Slot{testSlots[1]}
+	if actual := Slots(area); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestRoll(t *testing.T) {
+	walking := Filter(testSlots, "diamond", "walk")
+	cases := []struct {
+		name     string
+		rolls    []int
+		expected Encounter
+	}{
+		{
+			name:     "first slot, lowest level",
+			rolls:    []int{0, 0},
+			expected: Encounter{Pokemon: "pikachu", Level: 3, Version: "diamond", Method: "walk"},
+		},
+		{
+			name:     "last roll of the first slot, highest level",
+			rolls:    []int{69, 2},
+			expected: Encounter{Pokemon: "pikachu", Level: 5, Version: "diamond", Method: "walk"},
+		},
+		{
+			name:     "second slot has a single level",
+			rolls:    []int{70},
+			expected: Encounter{Pokemon: "pichu", Level: 2, Version: "diamond", Method: "walk"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := Roll(&randtest.Rand{Rolls: c.rolls}, walking)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %+v, got %+v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRollNothingToFind(t *testing.T) {
+	_, err := Roll(&randtest.Rand{}, Filter(testSlots, "pearl", "old-rod"))
+	if !errors.Is(err, ErrNoEncounters) {
+		t.Errorf("expected ErrNoEncounters, got %v", err)
+	}
+}
+
+func TestVersionsAndMethods(t *testing.T) {
+	if actual := Versions(testSlots); !reflect.DeepEqual(actual, []string{"diamond", "pearl"}) {
+		t.Errorf("unexpected versions %v", actual)
+	}
+	if actual := Methods(testSlots); !reflect.DeepEqual(actual, []string{"surf", "walk"}) {
+		t.Errorf("unexpected methods %v", actual)
+	}
+}
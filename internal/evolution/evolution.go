@@ -0,0 +1,171 @@
+package evolution
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+)
+
+// Stage is a species in an evolution chain, How are the ways to evolve into it
+// (empty for the first stage) and EvolvesTo what it can become
+type Stage struct {
+	Species   string   `json:"species"`
+	How       []string `json:"how,omitempty"`
+	EvolvesTo []Stage  `json:"evolves_to,omitempty"`
+}
+
+// Tree turns an evolution chain into stages with readable evolution methods
+func Tree(link pokeapi.ChainLink) Stage {
+	stage := Stage{Species: link.Species.Name}
+	for _, detail := range link.EvolutionDetails {
+		stage.How = append(stage.How, Describe(detail))
+	}
+	for _, next := range link.EvolvesTo {
+		stage.EvolvesTo = append(stage.EvolvesTo, Tree(next))
+	}
+	return stage
+}
+
+// Find looks up species in an evolution chain
+func Find(link pokeapi.ChainLink, species string) (pokeapi.ChainLink, bool) {
+	if link.Species.Name == species {
+		return link, true
+	}
+	for _, next := range link.EvolvesTo {
+		if found, ok := Find(next, species); ok {
+			return found, true
+		}
+	}
+	return pokeapi.ChainLink{}, false
+}
+
+// Describe says what it takes to evolve, e.g. "level 16" or "trade holding metal-coat"
+func Describe(detail pokeapi.EvolutionDetail) string {
+	var parts []string
+	switch detail.Trigger.Name {
+	case "level-up":
+		if detail.MinLevel != nil {
+			parts = append(parts, fmt.Sprintf("level %d", *detail.MinLevel))
+		} else {
+			parts = append(parts, "level up")
+		}
+	case "use-item":
+		if detail.Item != nil {
+			parts = append(parts, "use "+detail.Item.Name)
+		}
+	default:
+		parts = append(parts, strings.ReplaceAll(detail.Trigger.Name, "-", " "))
+	}
+
+	if detail.HeldItem != nil {
+		parts = append(parts, "holding "+detail.HeldItem.Name)
+	}
+	if detail.TradeSpecies != nil {
+		parts = append(parts, "for a "+detail.TradeSpecies.Name)
+	}
+	if detail.Gender != nil {
+		parts = append(parts, "if "+genderName(*detail.Gender))
+	}
+	if detail.KnownMove != nil {
+		parts = append(parts, "knowing "+detail.KnownMove.Name)
+	}
+	if detail.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+detail.KnownMoveType.Name+" move")
+	}
+	if detail.MinHappiness != nil {
+		parts = append(parts, "with high friendship")
+	}
+	if detail.MinAffection != nil {
+		parts = append(parts, "with high affection")
+	}
+	if detail.MinBeauty != nil {
+		parts = append(parts, "with high beauty")
+	}
+	if detail.Location != nil {
+		parts = append(parts, "at "+detail.Location.Name)
+	}
+	if detail.TimeOfDay != "" {
+		parts = append(parts, "during the "+detail.TimeOfDay)
+	}
+	if detail.PartySpecies != nil {
+		parts = append(parts, "with a "+detail.PartySpecies.Name+" in the party")
+	}
+	if detail.PartyType != nil {
+		parts = append(parts, "with a "+detail.PartyType.Name+" pokemon in the party")
+	}
+	if detail.RelativePhysicalStats != nil {
+		parts = append(parts, relativeStats(*detail.RelativePhysicalStats))
+	}
+	if detail.NeedsOverworldRain {
+		parts = append(parts, "in the rain")
+	}
+	if detail.TurnUpsideDown {
+		parts = append(parts, "holding the game upside down")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Subject is what's known about a caught pokemon that could evolve
+type Subject struct {
+	Level    int
+	HeldItem string
+	// Gender is "male", "female" or "" for species without one
+	Gender string
+	// UsedItem is the item being used on the pokemon, empty when it's simply asked to evolve
+	UsedItem string
+}
+
+// Ready reports whether subject meets detail. Only the level, held item, gender and an item
+// used on it are tracked, so an evolution needing anything else (friendship, a trade, a time
+// of day, ...) is never ready
+func Ready(detail pokeapi.EvolutionDetail, subject Subject) bool {
+	switch detail.Trigger.Name {
+	case "level-up":
+		if detail.MinLevel != nil && subject.Level < *detail.MinLevel {
+			return false
+		}
+	case "use-item":
+		if detail.Item == nil || subject.UsedItem != detail.Item.Name {
+			return false
+		}
+	default:
+		return false
+	}
+	if detail.HeldItem != nil && subject.HeldItem != detail.HeldItem.Name {
+		return false
+	}
+	if detail.Gender != nil && subject.Gender != genderName(*detail.Gender) {
+		return false
+	}
+	untracked := detail.KnownMove != nil || detail.KnownMoveType != nil || detail.Location != nil ||
+		detail.PartySpecies != nil || detail.PartyType != nil || detail.TradeSpecies != nil ||
+		detail.MinHappiness != nil || detail.MinBeauty != nil || detail.MinAffection != nil ||
+		detail.RelativePhysicalStats != nil || detail.TimeOfDay != "" ||
+		detail.NeedsOverworldRain || detail.TurnUpsideDown
+	return !untracked
+}
+
+// UsesHeldItem reports whether evolving by detail uses up the item the pokemon holds
+func UsesHeldItem(detail pokeapi.EvolutionDetail) bool {
+	return detail.HeldItem != nil
+}
+
+// the PokeAPI numbers genders 1 for female and 2 for male
+func genderName(gender int) string {
+	if gender == 1 {
+		return "female"
+	}
+	return "male"
+}
+
+// relativeStats is how attack compares to defense for tyrogue's evolutions
+func relativeStats(relative int) string {
+	switch {
+	case relative > 0:
+		return "with attack higher than defense"
+	case relative < 0:
+		return "with attack lower than defense"
+	}
+	return "with attack equal to defense"
+}
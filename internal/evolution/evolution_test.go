@@ -0,0 +1,121 @@
+package evolution
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+)
+
+// eevee's chain, cut down to three of its eight branches
+const eeveeChain = `{"species": {"name": "eevee"}, "evolution_details": [], "evolves_to": [
+	{"species": {"name": "vaporeon"}, "evolution_details": [
+		{"trigger": {"name": "use-item"}, "item": {"name": "water-stone"}}
+	], "evolves_to": []},
+	{"species": {"name": "espeon"}, "evolution_details": [
+		{"trigger": {"name": "level-up"}, "min_happiness": 160, "time_of_day": "day"}
+	], "evolves_to": []},
+	{"species": {"name": "glaceon"}, "evolution_details": [
+		{"trigger": {"name": "level-up"}, "location": {"name": "sinnoh-route-217"}},
+		{"trigger": {"name": "use-item"}, "item": {"name": "ice-stone"}}
+	], "evolves_to": []}
+]}`
+
+func loadChain(t *testing.T, data string) pokeapi.ChainLink {
+	t.Helper()
+	var chain pokeapi.ChainLink
+	if err := json.Unmarshal([]byte(data), &chain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return chain
+}
+
+func TestTree(t *testing.T) {
+	expected := Stage{Species: "eevee", EvolvesTo: []Stage{
+		{Species: "vaporeon", How: []string{"use water-stone"}},
+		{Species: "espeon", How: []string{"level up with high friendship during the day"}},
+		{Species: "glaceon", How: []string{"level up at sinnoh-route-217", "use ice-stone"}},
+	}}
+	if actual := Tree(loadChain(t, eeveeChain)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestFind(t *testing.T) {
+	chain := loadChain(t, eeveeChain)
+	if link, ok := Find(chain, "glaceon"); !ok || len(link.EvolutionDetails) != 2 {
+		t.Errorf("expected to find glaceon, got %+v", link)
+	}
+	if _, ok := Find(chain, "pikachu"); ok {
+		t.Errorf("expected pikachu not to be in eevee's chain")
+	}
+}
+
+func TestReady(t *testing.T) {
+	level := func(n int) *int { return &n }
+	female := 1
+	cases := []struct {
+		name     string
+		detail   pokeapi.EvolutionDetail
+		subject  Subject
+		expected bool
+	}{
+		{
+			name:     "high enough level",
+			detail:   pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "level-up"}, MinLevel: level(30)},
+			subject:  Subject{Level: 30},
+			expected: true,
+		},
+		{
+			name:    "level too low",
+			detail:  pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "level-up"}, MinLevel: level(30)},
+			subject: Subject{Level: 29},
+		},
+		{
+			name:     "using the stone",
+			detail:   pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "use-item"}, Item: &pokeapi.NamedAPIResource{Name: "thunder-stone"}},
+			subject:  Subject{Level: 5, UsedItem: "thunder-stone"},
+			expected: true,
+		},
+		{
+			name:    "using another item",
+			detail:  pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "use-item"}, Item: &pokeapi.NamedAPIResource{Name: "thunder-stone"}},
+			subject: Subject{Level: 5, UsedItem: "water-stone"},
+		},
+		{
+			name:    "holding the stone",
+			detail:  pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "use-item"}, Item: &pokeapi.NamedAPIResource{Name: "thunder-stone"}},
+			subject: Subject{Level: 5, HeldItem: "thunder-stone"},
+		},
+		{
+			name:     "leveling up without using anything",
+			detail:   pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "level-up"}, MinLevel: level(30)},
+			subject:  Subject{Level: 30, UsedItem: "rare-candy"},
+			expected: true,
+		},
+		{
+			name:    "wrong gender",
+			detail:  pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "level-up"}, MinLevel: level(20), Gender: &female},
+			subject: Subject{Level: 20, Gender: "male"},
+		},
+		{
+			name:    "friendship isn't tracked",
+			detail:  pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "level-up"}, MinHappiness: level(220)},
+			subject: Subject{Level: 100},
+		},
+		{
+			name:    "trades can't happen",
+			detail:  pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "trade"}},
+			subject: Subject{Level: 100},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := Ready(c.detail, c.subject); actual != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
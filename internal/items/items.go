@@ -0,0 +1,106 @@
+package items
+
+import (
+	"sort"
+
+	"github.com/staf3333/pokedexcli/internal/rng"
+)
+
+// Effect is what using an item on a pokemon does. The PokeAPI only describes it in words,
+// so like the ball modifiers in catch the numbers are kept here
+type Effect struct {
+	// HP is how much HP gets restored, Fraction restores 1/Fraction of the max HP instead
+	HP       int
+	Fraction int
+	// Revive brings a fainted pokemon back, and only works on one
+	Revive bool
+	// PP is restored to one move, or to every move with AllMoves. FullPP restores all of it
+	PP       int
+	FullPP   bool
+	AllMoves bool
+	// Levels is how many levels the pokemon grows
+	Levels int
+}
+
+var effects = map[string]Effect{
+	"potion":        {HP: 20},
+	"super-potion":  {HP: 50},
+	"hyper-potion":  {HP: 200},
+	"max-potion":    {Fraction: 1},
+	"full-restore":  {Fraction: 1},
+	"fresh-water":   {HP: 30},
+	"soda-pop":      {HP: 50},
+	"lemonade":      {HP: 70},
+	"moomoo-milk":   {HP: 100},
+	"energy-powder": {HP: 60},
+	"energy-root":   {HP: 120},
+	"berry-juice":   {HP: 20},
+	"oran-berry":    {HP: 10},
+	"sitrus-berry":  {Fraction: 4},
+	"revive":        {Revive: true, Fraction: 2},
+	"max-revive":    {Revive: true, Fraction: 1},
+	"revival-herb":  {Revive: true, Fraction: 1},
+	"ether":         {PP: 10},
+	"max-ether":     {FullPP: true},
+	"elixir":        {PP: 10, AllMoves: true},
+	"max-elixir":    {FullPP: true, AllMoves: true},
+	"leppa-berry":   {PP: 10},
+	"rare-candy":    {Levels: 1},
+}
+
+// Lookup finds the effect of using an item on a pokemon, false for items that can't be used
+// on one (or whose effect isn't simulated, like curing a status)
+func Lookup(name string) (Effect, bool) {
+	effect, ok := effects[name]
+	return effect, ok
+}
+
+// RestoresPP reports whether the item restores PP rather than HP
+func (e Effect) RestoresPP() bool {
+	return e.PP > 0 || e.FullPP
+}
+
+// Healed is how much HP a pokemon with maxHP that lost damage gets back, 0 when the item
+// has no effect: potions don't work on fainted pokemon and revives only work on them
+func (e Effect) Healed(maxHP, damage int) int {
+	if e.RestoresPP() || e.Levels > 0 || damage == 0 || (damage >= maxHP) != e.Revive {
+		return 0
+	}
+	healed := e.HP
+	if e.Fraction > 0 {
+		healed = max(maxHP/e.Fraction, 1)
+	}
+	return min(healed, damage)
+}
+
+// RestoredPP is how much PP a move with pp of maxPP left gets back
+func (e Effect) RestoredPP(pp, maxPP int) int {
+	if e.FullPP {
+		return maxPP - pp
+	}
+	return min(e.PP, maxPP-pp)
+}
+
+// Held is an item wild pokemon of a species hold Rarity percent of the time
+type Held struct {
+	Name   string
+	Rarity int
+}
+
+// RollHeld decides which item, if any, a wild pokemon holds. The items' rarities add up to
+// at most 100, the rest of the time it holds nothing
+func RollHeld(rng rng.Rand, held []Held) string {
+	if len(held) == 0 {
+		return ""
+	}
+	sorted := append([]Held(nil), held...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Rarity > sorted[j].Rarity })
+	roll := rng.Intn(100)
+	for _, item := range sorted {
+		if roll < item.Rarity {
+			return item.Name
+		}
+		roll -= item.Rarity
+	}
+	return ""
+}
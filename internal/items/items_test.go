@@ -0,0 +1,74 @@
+package items
+
+import (
+	"testing"
+
+	"github.com/staf3333/pokedexcli/internal/randtest"
+)
+
+func TestHealed(t *testing.T) {
+	potion, _ := Lookup("potion")
+	sitrus, _ := Lookup("sitrus-berry")
+	revive, _ := Lookup("revive")
+	ether, _ := Lookup("ether")
+	cases := []struct {
+		name          string
+		effect        Effect
+		maxHP, damage int
+		expected      int
+	}{
+		{name: "potion", effect: potion, maxHP: 100, damage: 50, expected: 20},
+		{name: "potion tops up", effect: potion, maxHP: 100, damage: 5, expected: 5},
+		{name: "potion at full health", effect: potion, maxHP: 100, damage: 0, expected: 0},
+		{name: "potion on a fainted pokemon", effect: potion, maxHP: 100, damage: 100, expected: 0},
+		{name: "sitrus berry", effect: sitrus, maxHP: 100, damage: 60, expected: 25},
+		{name: "revive", effect: revive, maxHP: 45, damage: 45, expected: 22},
+		{name: "revive on a healthy pokemon", effect: revive, maxHP: 45, damage: 10, expected: 0},
+		{name: "ether", effect: ether, maxHP: 45, damage: 10, expected: 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.effect.Healed(c.maxHP, c.damage); got != c.expected {
+				t.Errorf("expected %d HP back, got %d", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestRestoredPP(t *testing.T) {
+	ether, _ := Lookup("ether")
+	maxEther, _ := Lookup("max-ether")
+	if got := ether.RestoredPP(20, 35); got != 10 {
+		t.Errorf("expected an ether to restore 10 PP, got %d", got)
+	}
+	if got := ether.RestoredPP(30, 35); got != 5 {
+		t.Errorf("expected an ether to top up 5 PP, got %d", got)
+	}
+	if got := maxEther.RestoredPP(0, 35); got != 35 {
+		t.Errorf("expected a max ether to restore 35 PP, got %d", got)
+	}
+	if _, ok := Lookup("antidote"); ok {
+		t.Error("expected statuses not to be simulated")
+	}
+}
+
+func TestRollHeld(t *testing.T) {
+	held := []Held{{Name: "light-ball", Rarity: 5}, {Name: "oran-berry", Rarity: 50}}
+	cases := []struct {
+		roll     int
+		expected string
+	}{
+		{roll: 0, expected: "oran-berry"},
+		{roll: 49, expected: "oran-berry"},
+		{roll: 50, expected: "light-ball"},
+		{roll: 55, expected: ""},
+	}
+	for _, c := range cases {
+		if got := RollHeld(&randtest.Rand{Rolls: []int{c.roll}}, held); got != c.expected {
+			t.Errorf("roll %d: expected %q, got %q", c.roll, c.expected, got)
+		}
+	}
+	if got := RollHeld(&randtest.Rand{}, nil); got != "" {
+		t.Errorf("expected nothing held without held items, got %q", got)
+	}
+}
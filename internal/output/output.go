@@ -0,0 +1,135 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Formatter renders a command's result
+type Formatter interface {
+	Format(w io.Writer, result any) error
+}
+
+// FormatterFunc lets a plain function be used as a Formatter
+type FormatterFunc func(w io.Writer, result any) error
+
+func (f FormatterFunc) Format(w io.Writer, result any) error {
+	return f(w, result)
+}
+
+// Table is the rows-and-columns form of a result, used by the table and csv formats
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Tabular is implemented by results that can be shown as a table
+type Tabular interface {
+	Table() Table
+}
+
+// Texter is implemented by results that have their own human friendly layout,
+// which is what the text format shows
+type Texter interface {
+	WriteText(w io.Writer) error
+}
+
+// DefaultFormat is what results are shown as unless the user picks something else
+const DefaultFormat = "text"
+
+var (
+	mu         sync.RWMutex
+	formatters = map[string]Formatter{
+		"text":  FormatterFunc(formatText),
+		"table": FormatterFunc(formatTable),
+		"csv":   FormatterFunc(formatCSV),
+		"json":  FormatterFunc(formatJSON),
+		"yaml":  FormatterFunc(formatYAML),
+	}
+)
+
+// Register adds (or replaces) a format, so other packages can plug in their own
+func Register(name string, f Formatter) {
+	mu.Lock()
+	defer mu.Unlock()
+	formatters[name] = f
+}
+
+// Lookup finds the formatter registered under name
+func Lookup(name string) (Formatter, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	f, ok := formatters[name]
+	return f, ok
+}
+
+// Names lists the registered formats in alphabetical order
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatText uses the result's own layout when it has one, otherwise a table
+func formatText(w io.Writer, result any) error {
+	if texter, ok := result.(Texter); ok {
+		return texter.WriteText(w)
+	}
+	return formatTable(w, result)
+}
+
+func formatTable(w io.Writer, result any) error {
+	table, err := tableOf(result)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range table.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatCSV(w io.Writer, result any) error {
+	table, err := tableOf(result)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(table.Columns)
+	cw.WriteAll(table.Rows)
+	return cw.Error()
+}
+
+func formatJSON(w io.Writer, result any) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func tableOf(result any) (Table, error) {
+	tabular, ok := result.(Tabular)
+	if !ok {
+		return Table{}, fmt.Errorf("output: %T can't be shown as a table", result)
+	}
+	return tabular.Table(), nil
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+type testResult struct {
+	Name  string   `json:"name"`
+	Level int      `json:"level"`
+	Types []string `json:"types"`
+	Stats []struct {
+		Name string `json:"name"`
+		Base int    `json:"base"`
+	} `json:"stats"`
+	Notes map[string]string `json:"notes"`
+}
+
+func (r testResult) Table() Table {
+	return Table{
+		Columns: []string{"name", "level"},
+		Rows:    [][]string{{r.Name, "5"}, {"mr. mime, jr", "7"}},
+	}
+}
+
+func newTestResult() testResult {
+	r := testResult{Name: "pikachu", Level: 5, Types: []string{"electric", "true"}, Notes: map[string]string{}}
+	r.Stats = append(r.Stats, struct {
+		Name string `json:"name"`
+		Base int    `json:"base"`
+	}{Name: "hp", Base: 35})
+	return r
+}
+
+func TestFormats(t *testing.T) {
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "yaml",
+			expected: `name: pikachu
+level: 5
+types:
+  - electric
+  - "true"
+stats:
+  - name: hp
+    base: 35
+notes: {}
+`,
+		},
+		{
+			format: "csv",
+			expected: `name,level
+pikachu,5
+"mr. mime, jr",7
+`,
+		},
+		{
+			format: "table",
+			expected: `NAME          LEVEL
+pikachu       5
+mr. mime, jr  7
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			formatter, ok := Lookup(c.format)
+			if !ok {
+				t.Fatalf("expected %s to be registered", c.format)
+			}
+			var buf bytes.Buffer
+			if err := formatter.Format(&buf, newTestResult()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestTableNeedsTabular(t *testing.T) {
+	formatter, _ := Lookup("csv")
+	if err := formatter.Format(io.Discard, []string{"a"}); err == nil {
+		t.Errorf("expected an error for a result without a table form")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(formatters, "names")
+	})
+	Register("names", FormatterFunc(func(w io.Writer, result any) error {
+		_, err := io.WriteString(w, result.(testResult).Name)
+		return err
+	}))
+	formatter, ok := Lookup("names")
+	if !ok {
+		t.Fatalf("expected registered format to be found")
+	}
+	var buf bytes.Buffer
+	formatter.Format(&buf, newTestResult())
+	if buf.String() != "pikachu" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
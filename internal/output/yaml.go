@@ -0,0 +1,135 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// there is no YAML encoder in the standard library, and results already know how to be JSON,
+// so formatYAML goes through JSON: marshal the result, read it back keeping the key order,
+// and write the same tree out as block style YAML
+
+type yamlNode struct {
+	// one of '{' (mapping), '[' (sequence) or 0 (scalar)
+	kind     byte
+	keys     []string
+	children []*yamlNode
+	scalar   string
+}
+
+func formatYAML(w io.Writer, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := readYAMLNode(dec)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeYAMLNode(&buf, root, 0)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func readYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yamlNode{kind: byte(t)}
+		for dec.More() {
+			if node.kind == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, yamlScalar(key.(string)))
+			}
+			child, err := readYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+		// consume the closing } or ]
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yamlNode{scalar: yamlScalar(t)}, nil
+	case json.Number:
+		return &yamlNode{scalar: t.String()}, nil
+	case bool:
+		return &yamlNode{scalar: strconv.FormatBool(t)}, nil
+	case nil:
+		return &yamlNode{scalar: "null"}, nil
+	}
+	return nil, fmt.Errorf("output: unexpected JSON token %v", token)
+}
+
+func writeYAMLNode(buf *bytes.Buffer, node *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+	if inline, ok := inlineYAML(node); ok {
+		buf.WriteString(pad + inline + "\n")
+		return
+	}
+	for i, child := range node.children {
+		if node.kind == '{' {
+			if inline, ok := inlineYAML(child); ok {
+				fmt.Fprintf(buf, "%s%s: %s\n", pad, node.keys[i], inline)
+				continue
+			}
+			fmt.Fprintf(buf, "%s%s:\n", pad, node.keys[i])
+			writeYAMLNode(buf, child, indent+2)
+			continue
+		}
+		// sequence item: write the child indented, then swap the indent of its first
+		// line for the "- " marker
+		var item bytes.Buffer
+		writeYAMLNode(&item, child, indent+2)
+		buf.WriteString(pad + "- ")
+		buf.Write(item.Bytes()[indent+2:])
+	}
+}
+
+// inlineYAML gives the single line form of scalars and empty collections
+func inlineYAML(node *yamlNode) (string, bool) {
+	switch {
+	case node.kind == 0:
+		return node.scalar, true
+	case len(node.children) > 0:
+		return "", false
+	case node.kind == '{':
+		return "{}", true
+	default:
+		return "[]", true
+	}
+}
+
+// yamlScalar leaves plain words alone and quotes anything YAML could read as something else
+// (a number, a bool, a comment, ...). JSON string quoting is valid YAML double quoting
+func yamlScalar(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, "\n\t\"'\\") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") ||
+		strings.ContainsRune("-?:,[]{}#&*!|>%@`", rune(s[0])) {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
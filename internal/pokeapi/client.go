@@ -0,0 +1,307 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the public PokeAPI, point a Client somewhere else with WithBaseURL
+// (a self-hosted mirror, or an httptest server in tests)
+const DefaultBaseURL = "https://pokeapi.co/api/v2"
+
+// the API lists 20 resources per page by default, we ask for it explicitly so the paging
+// math in ListLocations doesn't depend on the server's default
+const locationPageSize = 20
+
+// DefaultTimeout bounds how long a single request may take, so a hanging connection can't
+// freeze the caller forever. Change it with WithTimeout
+const DefaultTimeout = 15 * time.Second
+
+// Cache is whatever the Client stores successful responses in, keyed by URL
+// *pokecache.Cache and *pokecache.DiskCache satisfy it
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Add(key string, val []byte)
+}
+
+// Client talks to the PokeAPI. The zero value is not usable, build one with NewClient
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	caches     []Cache
+	userAgent  string
+	logger     *log.Logger
+	timeout    time.Duration
+}
+
+// Option configures a Client, pass any number of them to NewClient
+type Option func(*Client)
+
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithCache sets the caches consulted before the network, fastest first
+// (e.g. memory then disk). A hit in a slower cache is copied into the faster ones
+func WithCache(caches ...Cache) Option {
+	return func(c *Client) {
+		c.caches = caches
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithTimeout sets the per-request timeout, 0 means only the caller's context limits a request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{},
+		userAgent:  "pokedexcli",
+		logger:     log.New(io.Discard, "", 0),
+		timeout:    DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetPokemon fetches /pokemon/{name}
+func (c *Client) GetPokemon(ctx context.Context, name string) (PokeAPIPokemonResponse, error) {
+	pokemon := PokeAPIPokemonResponse{}
+	err := c.get(ctx, "/pokemon/"+url.PathEscape(name), &pokemon)
+	return pokemon, err
+}
+
+// GetPokemonSpecies fetches /pokemon-species/{name}, use PokeAPIPokemonResponse.Species.Name
+// for the name since some pokemon (forms like deoxys-attack) are named differently from their species
+func (c *Client) GetPokemonSpecies(ctx context.Context, name string) (PokeAPIPokemonSpeciesResponse, error) {
+	species := PokeAPIPokemonSpeciesResponse{}
+	err := c.get(ctx, "/pokemon-species/"+url.PathEscape(name), &species)
+	return species, err
+}
+
+// GetLocationArea fetches /location-area/{name}
+func (c *Client) GetLocationArea(ctx context.Context, name string) (PokeAPILocationAreaResponse, error) {
+	area := PokeAPILocationAreaResponse{}
+	err := c.get(ctx, "/location-area/"+url.PathEscape(name), &area)
+	return area, err
+}
+
+// GetLocation fetches /location/{name}, which lists the areas of the location
+func (c *Client) GetLocation(ctx context.Context, name string) (PokeAPILocationDetailResponse, error) {
+	location := PokeAPILocationDetailResponse{}
+	err := c.get(ctx, "/location/"+url.PathEscape(name), &location)
+	return location, err
+}
+
+// GetRegion fetches /region/{name}, which lists the locations of the region
+func (c *Client) GetRegion(ctx context.Context, name string) (PokeAPIRegionResponse, error) {
+	region := PokeAPIRegionResponse{}
+	err := c.get(ctx, "/region/"+url.PathEscape(name), &region)
+	return region, err
+}
+
+// GetPokedex fetches /pokedex/{name}, e.g. national or original-sinnoh
+func (c *Client) GetPokedex(ctx context.Context, name string) (PokeAPIPokedexResponse, error) {
+	pokedex := PokeAPIPokedexResponse{}
+	err := c.get(ctx, "/pokedex/"+url.PathEscape(name), &pokedex)
+	return pokedex, err
+}
+
+// GetGeneration fetches /generation/{name}, the name can also be the number, e.g. 3
+func (c *Client) GetGeneration(ctx context.Context, name string) (PokeAPIGenerationResponse, error) {
+	generation := PokeAPIGenerationResponse{}
+	err := c.get(ctx, "/generation/"+url.PathEscape(name), &generation)
+	return generation, err
+}
+
+// GetNature fetches /nature/{name}
+func (c *Client) GetNature(ctx context.Context, name string) (PokeAPINatureResponse, error) {
+	nature := PokeAPINatureResponse{}
+	err := c.get(ctx, "/nature/"+url.PathEscape(name), &nature)
+	return nature, err
+}
+
+// ListNatures fetches the names of every nature, there are only 25 so they fit on one page
+func (c *Client) ListNatures(ctx context.Context) (PokeAPIResourceList, error) {
+	natures := PokeAPIResourceList{}
+	err := c.get(ctx, "/nature/?limit=100", &natures)
+	return natures, err
+}
+
+// GetMove fetches /move/{name}
+func (c *Client) GetMove(ctx context.Context, name string) (PokeAPIMoveResponse, error) {
+	move := PokeAPIMoveResponse{}
+	err := c.get(ctx, "/move/"+url.PathEscape(name), &move)
+	return move, err
+}
+
+// GetAbility fetches /ability/{name}
+func (c *Client) GetAbility(ctx context.Context, name string) (PokeAPIAbilityResponse, error) {
+	ability := PokeAPIAbilityResponse{}
+	err := c.get(ctx, "/ability/"+url.PathEscape(name), &ability)
+	return ability, err
+}
+
+// GetItem fetches /item/{name}
+func (c *Client) GetItem(ctx context.Context, name string) (PokeAPIItemResponse, error) {
+	item := PokeAPIItemResponse{}
+	err := c.get(ctx, "/item/"+url.PathEscape(name), &item)
+	return item, err
+}
+
+// GetItemCategory fetches /item-category/{name}
+func (c *Client) GetItemCategory(ctx context.Context, name string) (PokeAPIItemCategoryResponse, error) {
+	category := PokeAPIItemCategoryResponse{}
+	err := c.get(ctx, "/item-category/"+url.PathEscape(name), &category)
+	return category, err
+}
+
+// GetBerry fetches /berry/{name}, berries are named without the -berry their item has
+func (c *Client) GetBerry(ctx context.Context, name string) (PokeAPIBerryResponse, error) {
+	berry := PokeAPIBerryResponse{}
+	err := c.get(ctx, "/berry/"+url.PathEscape(name), &berry)
+	return berry, err
+}
+
+// GetType fetches /type/{name}
+func (c *Client) GetType(ctx context.Context, name string) (PokeAPITypeResponse, error) {
+	pokemonType := PokeAPITypeResponse{}
+	err := c.get(ctx, "/type/"+url.PathEscape(name), &pokemonType)
+	return pokemonType, err
+}
+
+// GetEvolutionChain fetches /evolution-chain/{id}, chains only have an ID so get it from
+// the species with ResourceID
+func (c *Client) GetEvolutionChain(ctx context.Context, id int) (PokeAPIEvolutionChainResponse, error) {
+	chain := PokeAPIEvolutionChainResponse{}
+	err := c.get(ctx, fmt.Sprintf("/evolution-chain/%d", id), &chain)
+	return chain, err
+}
+
+// ResourceID is the ID at the end of a resource URL the PokeAPI links to, e.g. 10 for
+// https://pokeapi.co/api/v2/evolution-chain/10/
+func ResourceID(resourceURL string) (int, error) {
+	path := strings.TrimRight(resourceURL, "/")
+	id, err := strconv.Atoi(path[strings.LastIndex(path, "/")+1:])
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("pokeapi: no resource ID in '%s'", resourceURL)
+	}
+	return id, nil
+}
+
+// GetVersionGroup fetches /version-group/{name}, e.g. red-blue or heartgold-soulsilver
+func (c *Client) GetVersionGroup(ctx context.Context, name string) (PokeAPIVersionGroupResponse, error) {
+	group := PokeAPIVersionGroupResponse{}
+	err := c.get(ctx, "/version-group/"+url.PathEscape(name), &group)
+	return group, err
+}
+
+// ListLocations fetches one page of /location, pages start at 0
+func (c *Client) ListLocations(ctx context.Context, page int) (PokeAPILocationResponse, error) {
+	locations := PokeAPILocationResponse{}
+	path := fmt.Sprintf("/location/?offset=%d&limit=%d", page*locationPageSize, locationPageSize)
+	err := c.get(ctx, path, &locations)
+	return locations, err
+}
+
+// implementing Cache:
+// usual approach: check cache for requested resource. If found in cache, return it to client
+// else, proceed down whatever logic you have to get the data!
+// only successful responses get cached, otherwise a 404 page would be served from the cache forever
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	fullURL := c.baseURL + path
+	body, ok := c.cached(fullURL)
+	if !ok {
+		var err error
+		body, err = c.fetch(ctx, fullURL)
+		if err != nil {
+			return err
+		}
+		for _, cache := range c.caches {
+			cache.Add(fullURL, body)
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("pokeapi: decoding %s: %w", fullURL, err)
+	}
+	return nil
+}
+
+func (c *Client) cached(fullURL string) ([]byte, bool) {
+	for i, cache := range c.caches {
+		body, ok := cache.Get(fullURL)
+		if !ok {
+			continue
+		}
+		c.logger.Printf("cache hit (layer %d): %s", i, fullURL)
+		for _, faster := range c.caches[:i] {
+			faster.Add(fullURL, body)
+		}
+		return body, true
+	}
+	return nil, false
+}
+
+func (c *Client) fetch(ctx context.Context, fullURL string) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, &TransportError{URL: fullURL, Err: err}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	c.logger.Printf("GET %s", fullURL)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &TransportError{URL: fullURL, Err: err}
+	}
+	// res contains req but use io.ReadAll to make code simpler
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, &TransportError{URL: fullURL, Err: err}
+	}
+	if res.StatusCode > 299 {
+		return nil, &HTTPError{URL: fullURL, StatusCode: res.StatusCode, Body: body}
+	}
+	return body, nil
+}
@@ -0,0 +1,53 @@
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors so callers can check what went wrong with errors.Is instead of
+// digging through status codes themselves
+var (
+	ErrNotFound    = errors.New("pokeapi: resource not found")
+	ErrRateLimited = errors.New("pokeapi: rate limited")
+)
+
+// HTTPError is returned whenever the PokeAPI answers with a non-2xx status code
+// the body is kept around because the API sometimes explains what went wrong in it
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("pokeapi: GET %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is lets errors.Is(err, ErrNotFound) and errors.Is(err, ErrRateLimited) match an HTTPError
+// with the corresponding status code
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// TransportError wraps anything that went wrong before we got a status code back
+// (DNS failure, connection refused, reading the body, ...)
+type TransportError struct {
+	URL string
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("pokeapi: GET %s: %v", e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
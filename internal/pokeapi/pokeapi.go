@@ -1,13 +1,5 @@
 package pokeapi
 
-import (
-	"fmt"
-	"io"
-	"net/http"
-
-	"github.com/staf3333/pokedexcli/internal/pokecache"
-)
-
 // Struct for the JSON returned from the PokeAPI
 // apparently the strings next to each field in the struct provide metadata about how
 // the fields of the struct should be handled
@@ -160,146 +152,8 @@ type PokeAPIPokemonResponse struct {
 				FrontDefault string `json:"front_default"`
 			} `json:"official-artwork"`
 		} `json:"other"`
-		Versions struct {
-			GenerationI struct {
-				RedBlue struct {
-					BackDefault  string `json:"back_default"`
-					BackGray     string `json:"back_gray"`
-					FrontDefault string `json:"front_default"`
-					FrontGray    string `json:"front_gray"`
-				} `json:"red-blue"`
-				Yellow struct {
-					BackDefault  string `json:"back_default"`
-					BackGray     string `json:"back_gray"`
-					FrontDefault string `json:"front_default"`
-					FrontGray    string `json:"front_gray"`
-				} `json:"yellow"`
-			} `json:"generation-i"`
-			GenerationIi struct {
-				Crystal struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"crystal"`
-				Gold struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"gold"`
-				Silver struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"silver"`
-			} `json:"generation-ii"`
-			GenerationIii struct {
-				Emerald struct {
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"emerald"`
-				FireredLeafgreen struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"firered-leafgreen"`
-				RubySapphire struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
-				} `json:"ruby-sapphire"`
-			} `json:"generation-iii"`
-			GenerationIv struct {
-				DiamondPearl struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
-				} `json:"diamond-pearl"`
-				HeartgoldSoulsilver struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
-				} `json:"heartgold-soulsilver"`
-				Platinum struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
-				} `json:"platinum"`
-			} `json:"generation-iv"`
-			GenerationV struct {
-				BlackWhite struct {
-					Animated struct {
-						BackDefault      string `json:"back_default"`
-						BackFemale       any    `json:"back_female"`
-						BackShiny        string `json:"back_shiny"`
-						BackShinyFemale  any    `json:"back_shiny_female"`
-						FrontDefault     string `json:"front_default"`
-						FrontFemale      any    `json:"front_female"`
-						FrontShiny       string `json:"front_shiny"`
-						FrontShinyFemale any    `json:"front_shiny_female"`
-					} `json:"animated"`
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
-				} `json:"black-white"`
-			} `json:"generation-v"`
-			GenerationVi struct {
-				OmegarubyAlphasapphire struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
-				} `json:"omegaruby-alphasapphire"`
-				XY struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
-				} `json:"x-y"`
-			} `json:"generation-vi"`
-			GenerationVii struct {
-				Icons struct {
-					FrontDefault string `json:"front_default"`
-					FrontFemale  any    `json:"front_female"`
-				} `json:"icons"`
-				UltraSunUltraMoon struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
-				} `json:"ultra-sun-ultra-moon"`
-			} `json:"generation-vii"`
-			GenerationViii struct {
-				Icons struct {
-					FrontDefault string `json:"front_default"`
-					FrontFemale  any    `json:"front_female"`
-				} `json:"icons"`
-			} `json:"generation-viii"`
-		} `json:"versions"`
+		// generation name -> version group name -> sprites, e.g. ["generation-i"]["red-blue"]
+		Versions map[string]map[string]VersionSprites `json:"versions"`
 	} `json:"sprites"`
 	Stats []struct {
 		BaseStat int `json:"base_stat"`
@@ -339,41 +193,268 @@ type Location struct {
 	URL  string `json:"url"`
 }
 
-// implementing Cache:
-// usual approach: check cache for requested resource. If found in cache, return it to client
-// else, proceed down whatever logic you have to get the data!
+// NamedAPIResource is how the PokeAPI links to another resource, same shape as Location
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// only the parts of /pokemon-species we use, the full response is much bigger
+type PokeAPIPokemonSpeciesResponse struct {
+	ID             int              `json:"id"`
+	Name           string           `json:"name"`
+	CaptureRate    int              `json:"capture_rate"`
+	BaseHappiness  int              `json:"base_happiness"`
+	GenderRate     int              `json:"gender_rate"`
+	IsBaby         bool             `json:"is_baby"`
+	IsLegendary    bool             `json:"is_legendary"`
+	IsMythical     bool             `json:"is_mythical"`
+	Generation     NamedAPIResource `json:"generation"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	EvolvesFromSpecies *NamedAPIResource `json:"evolves_from_species"`
+	PokedexNumbers     []struct {
+		EntryNumber int              `json:"entry_number"`
+		Pokedex     NamedAPIResource `json:"pokedex"`
+	} `json:"pokedex_numbers"`
+	Varieties []struct {
+		IsDefault bool             `json:"is_default"`
+		Pokemon   NamedAPIResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// VersionSprites are the sprites of a pokemon as one version group drew them. Older
+// games don't have every kind, missing ones are left empty
+type VersionSprites struct {
+	BackDefault  string `json:"back_default"`
+	BackShiny    string `json:"back_shiny"`
+	FrontDefault string `json:"front_default"`
+	FrontShiny   string `json:"front_shiny"`
+}
 
-func GetData(url string, cache *pokecache.Cache) []byte {
-	var body []byte
-	// attempt to get data from the Cache first, if not found in the cache, get from the API
-	body, ok := cache.Get(url)
-	if !ok {		
-		body = getFromPokeAPI(url)
-		cache.Add(url, body)
-	} else {
-		fmt.Println("Found data in cache!")
-	}
-	// apiResponse is what we want to cache for a given url!
-	// apiResponse := PokemonLocationResponse{}
-	return body
+// PokeAPIVersionGroupResponse is a set of games released together, e.g. red-blue
+type PokeAPIVersionGroupResponse struct {
+	ID               int                `json:"id"`
+	Name             string             `json:"name"`
+	Order            int                `json:"order"`
+	Generation       NamedAPIResource   `json:"generation"`
+	MoveLearnMethods []NamedAPIResource `json:"move_learn_methods"`
+	Pokedexes        []NamedAPIResource `json:"pokedexes"`
+	Regions          []NamedAPIResource `json:"regions"`
+	Versions         []NamedAPIResource `json:"versions"`
+}
+
+// PokeAPILocationDetailResponse is a single location, PokeAPILocationResponse is the paged list
+type PokeAPILocationDetailResponse struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name"`
+	Region NamedAPIResource   `json:"region"`
+	Areas  []NamedAPIResource `json:"areas"`
+	Names  []struct {
+		Name     string           `json:"name"`
+		Language NamedAPIResource `json:"language"`
+	} `json:"names"`
 }
 
-func getFromPokeAPI(url string) []byte {
-	// base url for PokeAPI: https://pokeapi.co/api/v2/{endpoint}/
-	// url for locations: https://pokeapi.co/api/v2/location/
-	// list by default contains 20 resources
-	res, err := http.Get(url)
-	if err != nil {
-		fmt.Println("Error in api call")
-	}
-	// res contains req but use io.ReadAll to make code simpler
-	defer res.Body.Close()
-	body, err := io.ReadAll(res.Body)
-	if res.StatusCode > 299 {
-		fmt.Printf("Response failed with status code: %d and\nbody: %s\n", res.StatusCode, body)
-	}
-	if err != nil {
-		fmt.Println("Error reading response body")
-	}
-	return body
-}
\ No newline at end of file
+// PokeAPIRegionResponse is a region such as kanto, with the locations in it
+type PokeAPIRegionResponse struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	Locations      []NamedAPIResource `json:"locations"`
+	MainGeneration NamedAPIResource   `json:"main_generation"`
+	Pokedexes      []NamedAPIResource `json:"pokedexes"`
+	VersionGroups  []NamedAPIResource `json:"version_groups"`
+}
+
+// PokeAPIPokedexResponse is a pokedex such as national or kanto, with the species in it
+// in the order of the pokedex
+type PokeAPIPokedexResponse struct {
+	ID             int               `json:"id"`
+	Name           string            `json:"name"`
+	IsMainSeries   bool              `json:"is_main_series"`
+	Names          []Name            `json:"names"`
+	Region         *NamedAPIResource `json:"region"`
+	PokemonEntries []struct {
+		EntryNumber    int              `json:"entry_number"`
+		PokemonSpecies NamedAPIResource `json:"pokemon_species"`
+	} `json:"pokemon_entries"`
+}
+
+// PokeAPIGenerationResponse is a generation of games, with the species introduced in it
+type PokeAPIGenerationResponse struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	MainRegion     NamedAPIResource   `json:"main_region"`
+	PokemonSpecies []NamedAPIResource `json:"pokemon_species"`
+	VersionGroups  []NamedAPIResource `json:"version_groups"`
+}
+
+// PokeAPIResourceList is one page of a list endpoint such as /nature
+type PokeAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     *string            `json:"next"`
+	Previous *string            `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+// PokeAPINatureResponse is a nature, the stats are nil for the neutral ones
+type PokeAPINatureResponse struct {
+	ID            int               `json:"id"`
+	Name          string            `json:"name"`
+	IncreasedStat *NamedAPIResource `json:"increased_stat"`
+	DecreasedStat *NamedAPIResource `json:"decreased_stat"`
+}
+
+// PokeAPIMoveResponse is a move, Power and Accuracy are nil for moves without them
+// (status moves, and moves that never miss)
+type PokeAPIMoveResponse struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Accuracy    *int             `json:"accuracy"`
+	Power       *int             `json:"power"`
+	PP          int              `json:"pp"`
+	Priority    int              `json:"priority"`
+	Type        NamedAPIResource `json:"type"`
+	DamageClass NamedAPIResource `json:"damage_class"`
+}
+
+// PokeAPITypeResponse is a type and how it does against the other types
+type PokeAPITypeResponse struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageTo   []NamedAPIResource `json:"double_damage_to"`
+		HalfDamageTo     []NamedAPIResource `json:"half_damage_to"`
+		NoDamageTo       []NamedAPIResource `json:"no_damage_to"`
+		DoubleDamageFrom []NamedAPIResource `json:"double_damage_from"`
+		HalfDamageFrom   []NamedAPIResource `json:"half_damage_from"`
+		NoDamageFrom     []NamedAPIResource `json:"no_damage_from"`
+	} `json:"damage_relations"`
+}
+
+// PokeAPIEvolutionChainResponse is the family tree of a species, starting from its
+// lowest stage. Find a species' chain through PokeAPIPokemonSpeciesResponse.EvolutionChain
+type PokeAPIEvolutionChainResponse struct {
+	ID              int               `json:"id"`
+	BabyTriggerItem *NamedAPIResource `json:"baby_trigger_item"`
+	Chain           ChainLink         `json:"chain"`
+}
+
+// ChainLink is one species of an evolution chain and what it evolves into. EvolutionDetails
+// are the ways to evolve into this species, any one of them is enough
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedAPIResource  `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// EvolutionDetail is one way of evolving: the Trigger (level-up, use-item, trade, ...) and
+// everything else that has to be true at the time. Requirements that don't apply are nil or empty
+type EvolutionDetail struct {
+	Trigger               NamedAPIResource  `json:"trigger"`
+	Item                  *NamedAPIResource `json:"item"`
+	HeldItem              *NamedAPIResource `json:"held_item"`
+	KnownMove             *NamedAPIResource `json:"known_move"`
+	KnownMoveType         *NamedAPIResource `json:"known_move_type"`
+	Location              *NamedAPIResource `json:"location"`
+	PartySpecies          *NamedAPIResource `json:"party_species"`
+	PartyType             *NamedAPIResource `json:"party_type"`
+	TradeSpecies          *NamedAPIResource `json:"trade_species"`
+	Gender                *int              `json:"gender"`
+	MinLevel              *int              `json:"min_level"`
+	MinHappiness          *int              `json:"min_happiness"`
+	MinBeauty             *int              `json:"min_beauty"`
+	MinAffection          *int              `json:"min_affection"`
+	RelativePhysicalStats *int              `json:"relative_physical_stats"`
+	TimeOfDay             string            `json:"time_of_day"`
+	NeedsOverworldRain    bool              `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool              `json:"turn_upside_down"`
+}
+
+// PokeAPIAbilityResponse is an ability, its effect in each language the PokeAPI has it in
+// and the pokemon that can have it
+type PokeAPIAbilityResponse struct {
+	ID                int              `json:"id"`
+	Name              string           `json:"name"`
+	IsMainSeries      bool             `json:"is_main_series"`
+	Generation        NamedAPIResource `json:"generation"`
+	Names             []Name           `json:"names"`
+	EffectEntries     []VerboseEffect  `json:"effect_entries"`
+	FlavorTextEntries []struct {
+		FlavorText   string           `json:"flavor_text"`
+		Language     NamedAPIResource `json:"language"`
+		VersionGroup NamedAPIResource `json:"version_group"`
+	} `json:"flavor_text_entries"`
+	Pokemon []struct {
+		IsHidden bool             `json:"is_hidden"`
+		Slot     int              `json:"slot"`
+		Pokemon  NamedAPIResource `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+// Name is the name of a resource in one language
+type Name struct {
+	Name     string           `json:"name"`
+	Language NamedAPIResource `json:"language"`
+}
+
+// VerboseEffect is what an ability or item does, in one language
+type VerboseEffect struct {
+	Effect      string           `json:"effect"`
+	ShortEffect string           `json:"short_effect"`
+	Language    NamedAPIResource `json:"language"`
+}
+
+// PokeAPIItemResponse is an item. Attributes say what can be done with it (holdable,
+// consumable, usable-overworld, ...) and the category which bag pocket it goes in
+type PokeAPIItemResponse struct {
+	ID            int                `json:"id"`
+	Name          string             `json:"name"`
+	Cost          int                `json:"cost"`
+	FlingPower    *int               `json:"fling_power"`
+	Attributes    []NamedAPIResource `json:"attributes"`
+	Category      NamedAPIResource   `json:"category"`
+	Names         []Name             `json:"names"`
+	EffectEntries []VerboseEffect    `json:"effect_entries"`
+	// unlike most flavor texts, an item's are in "text"
+	FlavorTextEntries []struct {
+		Text         string           `json:"text"`
+		Language     NamedAPIResource `json:"language"`
+		VersionGroup NamedAPIResource `json:"version_group"`
+	} `json:"flavor_text_entries"`
+	Sprites struct {
+		Default string `json:"default"`
+	} `json:"sprites"`
+	HeldByPokemon []struct {
+		Pokemon NamedAPIResource `json:"pokemon"`
+	} `json:"held_by_pokemon"`
+}
+
+// PokeAPIItemCategoryResponse is a kind of item, like healing or standard-balls
+type PokeAPIItemCategoryResponse struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name"`
+	Pocket NamedAPIResource   `json:"pocket"`
+	Items  []NamedAPIResource `json:"items"`
+}
+
+// PokeAPIBerryResponse is a berry as a plant, the berry you carry around is its Item
+type PokeAPIBerryResponse struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	GrowthTime       int              `json:"growth_time"`
+	MaxHarvest       int              `json:"max_harvest"`
+	NaturalGiftPower int              `json:"natural_gift_power"`
+	Size             int              `json:"size"`
+	Smoothness       int              `json:"smoothness"`
+	SoilDryness      int              `json:"soil_dryness"`
+	Firmness         NamedAPIResource `json:"firmness"`
+	Flavors          []struct {
+		Potency int              `json:"potency"`
+		Flavor  NamedAPIResource `json:"flavor"`
+	} `json:"flavors"`
+	Item            NamedAPIResource `json:"item"`
+	NaturalGiftType NamedAPIResource `json:"natural_gift_type"`
+}
@@ -0,0 +1,188 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/staf3333/pokedexcli/internal/pokecache"
+)
+
+func TestClientErrors(t *testing.T) {
+	cases := []struct {
+		status  int
+		wantErr error
+	}{
+		{status: http.StatusNotFound, wantErr: ErrNotFound},
+		{status: http.StatusTooManyRequests, wantErr: ErrRateLimited},
+	}
+
+	for _, c := range cases {
+		t.Run(http.StatusText(c.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Not Found", c.status)
+			}))
+			defer server.Close()
+
+			cache := newTestCache(t)
+			client := NewClient(WithBaseURL(server.URL), WithCache(cache))
+			_, err := client.GetPokemon(context.Background(), "pikachuu")
+			if !errors.Is(err, c.wantErr) {
+				t.Errorf("expected %v, got %v", c.wantErr, err)
+			}
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != c.status {
+				t.Errorf("expected HTTPError with status %d, got %v", c.status, err)
+			}
+			if _, ok := cache.Get(server.URL + "/pokemon/pikachuu"); ok {
+				t.Errorf("expected failed response to not be cached")
+			}
+		})
+	}
+}
+
+func TestClientCachesSuccess(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/pokemon/pikachu" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.UserAgent() != "pokedex-test" {
+			t.Errorf("unexpected user agent %q", r.UserAgent())
+		}
+		w.Write([]byte(`{"name":"pikachu","base_experience":112}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithCache(newTestCache(t)),
+		WithUserAgent("pokedex-test"),
+	)
+	for i := 0; i < 2; i++ {
+		pokemon, err := client.GetPokemon(context.Background(), "pikachu")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pokemon.Name != "pikachu" || pokemon.BaseExperience != 112 {
+			t.Errorf("unexpected pokemon %+v", pokemon)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 request to the server, got %d", calls)
+	}
+}
+
+func TestListLocationsPaging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("offset"); got != "40" {
+			t.Errorf("expected offset 40, got %s", got)
+		}
+		w.Write([]byte(`{"count":1,"results":[{"name":"canalave-city"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	locations, err := client.ListLocations(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(locations.Results) != 1 || locations.Results[0].Name != "canalave-city" {
+		t.Errorf("unexpected locations %+v", locations)
+	}
+}
+
+func TestResourceID(t *testing.T) {
+	for url, expected := range map[string]int{
+		"https://pokeapi.co/api/v2/evolution-chain/10/": 10,
+		"https://pokeapi.co/api/v2/pokemon-species/172": 172,
+	} {
+		if id, err := ResourceID(url); err != nil || id != expected {
+			t.Errorf("expected %d for %s, got %d (%v)", expected, url, id, err)
+		}
+	}
+	for _, url := range []string{"", "https://pokeapi.co/api/v2/evolution-chain/"} {
+		if _, err := ResourceID(url); err == nil {
+			t.Errorf("expected an error for '%s'", url)
+		}
+	}
+}
+
+func TestClientTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseURL := server.URL
+	server.Close()
+
+	_, err := NewClient(WithBaseURL(baseURL)).GetPokemon(context.Background(), "pikachu")
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) {
+		t.Errorf("expected TransportError, got %v", err)
+	}
+}
+
+func TestClientCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	t.Run("timeout", func(t *testing.T) {
+		client := NewClient(WithBaseURL(server.URL), WithTimeout(10*time.Millisecond))
+		_, err := client.GetPokemon(context.Background(), "pikachu")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := NewClient(WithBaseURL(server.URL)).GetPokemon(ctx, "pikachu")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled, got %v", err)
+		}
+	})
+}
+
+func TestClientCacheLayers(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"name":"pikachu"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	disk, err := pokecache.NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := NewClient(WithBaseURL(server.URL), WithCache(newTestCache(t), disk))
+	if _, err := first.GetPokemon(context.Background(), "pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a new session starts with an empty memory cache but the same disk cache
+	memory := newTestCache(t)
+	second := NewClient(WithBaseURL(server.URL), WithCache(memory, disk))
+	if _, err := second.GetPokemon(context.Background(), "pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 request to the server, got %d", calls)
+	}
+	if _, ok := memory.Get(server.URL + "/pokemon/pikachu"); !ok {
+		t.Errorf("expected disk hit to be copied into the memory cache")
+	}
+}
+
+// newTestCache returns a memory cache whose reaper is stopped when the test ends
+func newTestCache(t *testing.T) *pokecache.Cache {
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(cache.Stop)
+	return cache
+}
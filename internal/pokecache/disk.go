@@ -0,0 +1,188 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiskCache keeps entries on disk so they survive restarts, one file per key.
+// It has the same Get/Add methods as Cache so the two can be stacked in front of the network
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+	mu  sync.Mutex
+}
+
+// the file for each key holds the key itself too, so a (very unlikely) hash collision
+// is a cache miss instead of the wrong data
+type diskEntry struct {
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+	Val       []byte    `json:"val"`
+}
+
+// DiskStats summarises what is in a DiskCache
+type DiskStats struct {
+	Entries int
+	Expired int
+	Bytes   int64
+}
+
+const diskEntrySuffix = ".json"
+
+// DefaultDir is the per-user cache directory, e.g. ~/.cache/pokedexcli on Linux
+func DefaultDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("pokecache: finding cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "pokedexcli"), nil
+}
+
+// NewDiskCache stores entries under dir, which is created if needed.
+// Entries older than ttl are treated as missing and removed by Prune
+func NewDiskCache(dir string, ttl time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("pokecache: %w", err)
+	}
+	return &DiskCache{dir: dir, ttl: ttl}, nil
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	path := d.path(key)
+	entry, err := readDiskEntry(path)
+	if err != nil || entry.Key != key {
+		return nil, false
+	}
+	if d.expired(entry) {
+		os.Remove(path)
+		return nil, false
+	}
+	return entry.Val, true
+}
+
+// Add writes the entry to disk. The cache is only an optimisation, so a failed write
+// (full disk, read-only home directory, ...) is dropped rather than reported
+func (d *DiskCache) Add(key string, val []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	data, err := json.Marshal(diskEntry{Key: key, CreatedAt: time.Now(), Val: val})
+	if err != nil {
+		return
+	}
+	// write to a temp file and rename it so a reader never sees half an entry
+	tmp, err := os.CreateTemp(d.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+	os.Rename(tmp.Name(), d.path(key))
+}
+
+// Stats counts the entries currently on disk, including expired ones Prune hasn't removed yet
+func (d *DiskCache) Stats() (DiskStats, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := DiskStats{}
+	err := d.walk(func(path string, info os.FileInfo) error {
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if entry, err := readDiskEntry(path); err != nil || d.expired(entry) {
+			stats.Expired++
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Clear removes every entry and returns how many there were
+func (d *DiskCache) Clear() (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	removed := 0
+	err := d.walk(func(path string, info os.FileInfo) error {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// Prune removes expired (or unreadable) entries and returns how many it removed
+func (d *DiskCache) Prune() (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	removed := 0
+	err := d.walk(func(path string, info os.FileInfo) error {
+		entry, err := readDiskEntry(path)
+		if err == nil && !d.expired(entry) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// walk calls fn for every entry file in the cache directory
+func (d *DiskCache) walk(fn func(path string, info os.FileInfo) error) error {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("pokecache: %w", err)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), diskEntrySuffix) {
+			continue
+		}
+		info, err := file.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("pokecache: %w", err)
+		}
+		if err := fn(filepath.Join(d.dir, file.Name()), info); err != nil {
+			return fmt.Errorf("pokecache: %w", err)
+		}
+	}
+	return nil
+}
+
+// keys are URLs, hash them so they make safe file names
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskEntrySuffix)
+}
+
+func (d *DiskCache) expired(entry diskEntry) bool {
+	return time.Since(entry.CreatedAt) > d.ttl
+}
+
+func readDiskEntry(path string) (diskEntry, error) {
+	entry := diskEntry{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal(data, &entry)
+	return entry, err
+}
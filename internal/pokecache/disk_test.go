@@ -0,0 +1,81 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskCacheSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.Add("https://example.com", []byte("testdata"))
+
+	reopened, err := NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	val, ok := reopened.Get("https://example.com")
+	if !ok {
+		t.Fatalf("expected to find key")
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected testdata, got %s", val)
+	}
+}
+
+func TestDiskCacheExpiry(t *testing.T) {
+	const ttl = 5 * time.Millisecond
+	cache, err := NewDiskCache(t.TempDir(), ttl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Add("https://example.com/path", []byte("moretestdata"))
+
+	time.Sleep(ttl + 5*time.Millisecond)
+	cache.Add("https://example.com/fresh", []byte("fresh"))
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Entries != 3 || stats.Expired != 2 {
+		t.Errorf("expected 3 entries with 2 expired, got %+v", stats)
+	}
+
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected expired key to be missing")
+	}
+
+	removed, err := cache.Prune()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Get already removed one of the expired entries
+	if removed != 1 {
+		t.Errorf("expected prune to remove 1 entry, removed %d", removed)
+	}
+	if _, ok := cache.Get("https://example.com/fresh"); !ok {
+		t.Errorf("expected fresh key to survive prune")
+	}
+}
+
+func TestDiskCacheClear(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Add("https://example.com/path", []byte("moretestdata"))
+
+	removed, err := cache.Clear()
+	if err != nil || removed != 2 {
+		t.Errorf("expected to clear 2 entries, got %d (%v)", removed, err)
+	}
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected key to be gone after clear")
+	}
+}
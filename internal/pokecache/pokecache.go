@@ -1,25 +1,74 @@
 package pokecache
 
 import (
+	"container/list"
 	"sync"
 	"time"
 )
 
 type cacheEntry struct {
+	key       string
 	createdAt time.Time
-	val []byte
+	val       []byte
 }
 
 type Cache struct {
-	cacheMap map[string]cacheEntry
-	mu sync.Mutex
+	// entries live in lru, most recently used at the front, cacheMap finds them by key
+	cacheMap map[string]*list.Element
+	lru      *list.List
+	mu       sync.Mutex
 	interval time.Duration
+	// 0 means no limit
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	stats      Stats
+	// closing done tells the reaper to exit, it closes reaperDone once it has
+	done       chan struct{}
+	reaperDone chan struct{}
+	stopOnce   sync.Once
 }
 
-func NewCache(interval time.Duration) *Cache {
+// Stats counts what the cache has been up to since it was created
+type Stats struct {
+	Hits   int
+	Misses int
+	// entries dropped to stay under the size limits
+	Evictions int
+	// entries dropped by the reaper for being older than the interval
+	Expirations int
+	Entries     int
+	Bytes       int
+}
+
+// Option configures a Cache, pass any number of them to NewCache
+type Option func(*Cache)
+
+// WithMaxEntries caps the number of entries, least recently used entries are evicted first
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
+}
+
+// WithMaxBytes caps the total size of the cached values, least recently used entries are
+// evicted first. A single value bigger than the cap is not cached at all
+func WithMaxBytes(n int) Option {
+	return func(c *Cache) {
+		c.maxBytes = n
+	}
+}
+
+func NewCache(interval time.Duration, opts ...Option) *Cache {
 	c := &Cache{
-		cacheMap: make(map[string]cacheEntry),
-		interval: interval,
+		cacheMap:   make(map[string]*list.Element),
+		lru:        list.New(),
+		interval:   interval,
+		done:       make(chan struct{}),
+		reaperDone: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 	go c.reapLoop()
 	return c
@@ -29,20 +78,76 @@ func (c *Cache) Add(key string, val []byte) {
 	// need to use a mutex to lock the map while doing operation
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.cacheMap[key] = cacheEntry{
+	if c.maxBytes > 0 && len(val) > c.maxBytes {
+		return
+	}
+	if elem, ok := c.cacheMap[key]; ok {
+		c.remove(elem)
+	}
+	c.cacheMap[key] = c.lru.PushFront(&cacheEntry{
+		key:       key,
 		createdAt: time.Now(),
-		val: val,
+		val:       val,
+	})
+	c.bytes += len(val)
+
+	// evict from the back (least recently used) until we fit again
+	for c.overLimit() {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
 	}
 }
 
 func (c *Cache) Get(key string) ([]byte, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	entry, ok := c.cacheMap[key]
+	elem, ok := c.cacheMap[key]
 	if !ok {
+		c.stats.Misses++
 		return nil, false
-	} 
-	return entry.val, true
+	}
+	c.stats.Hits++
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).val, true
+}
+
+// Clear removes every entry
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cacheMap = make(map[string]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// Stop shuts down the background reaper and waits for it to exit. The cache still works
+// afterwards but entries no longer expire. Calling Stop more than once is fine
+func (c *Cache) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.done)
+	})
+	<-c.reaperDone
+}
+
+func (c *Cache) overLimit() bool {
+	return (c.maxEntries > 0 && c.lru.Len() > c.maxEntries) ||
+		(c.maxBytes > 0 && c.bytes > c.maxBytes)
+}
+
+// remove drops an entry, the caller must hold c.mu
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.cacheMap, entry.key)
+	c.bytes -= len(entry.val)
 }
 
 func (c *Cache) reapLoop() {
@@ -51,18 +156,29 @@ func (c *Cache) reapLoop() {
 	// have to specify time interval in seconds b/c c.interval doesn't convert to second automatically
 	ticker := time.NewTicker(c.interval)
 	defer ticker.Stop()
-	// use for range loop for idiomatic handling of the ticker channel
-	// automatically reads values from the channel until it is closed -> don't have to add
-	// extra logic to check if channel is closed
-	for t := range ticker.C {
-		// here we want to range through the entries in the cache map, and compare the createdAt 
-		// time to the t recieved from the ticker
-		c.mu.Lock()
-		for k, v := range c.cacheMap {
-			if t.Sub(v.createdAt) > c.interval {
-				delete(c.cacheMap, k)
-			}
+	defer close(c.reaperDone)
+	// a ticker channel is never closed, so select on it together with done to know when to exit
+	for {
+		select {
+		case <-c.done:
+			return
+		case t := <-ticker.C:
+			c.reap(t)
+		}
+	}
+}
+
+func (c *Cache) reap(t time.Time) {
+	// here we want to range through the entries in the cache, and compare the createdAt
+	// time to the t recieved from the ticker
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if t.Sub(elem.Value.(*cacheEntry).createdAt) > c.interval {
+			c.remove(elem)
+			c.stats.Expirations++
 		}
-		c.mu.Unlock()
+		elem = next
 	}
-}
\ No newline at end of file
+}
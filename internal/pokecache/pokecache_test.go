@@ -2,6 +2,7 @@ package pokecache
 
 import (
 	"fmt"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -25,6 +26,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Stop()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -43,6 +45,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Stop()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -59,3 +62,95 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestMaxEntriesEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxEntries(2))
+	defer cache.Stop()
+	cache.Add("a", []byte("1"))
+	cache.Add("b", []byte("2"))
+	// touching a makes b the least recently used
+	cache.Get("a")
+	cache.Add("c", []byte("3"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected to find %s", key)
+		}
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxBytes(10))
+	defer cache.Stop()
+	cache.Add("a", []byte("12345"))
+	cache.Add("b", []byte("12345"))
+	cache.Add("c", []byte("123"))
+
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected a to be evicted")
+	}
+	if stats := cache.Stats(); stats.Bytes != 8 || stats.Entries != 2 {
+		t.Errorf("expected 2 entries using 8 bytes, got %+v", stats)
+	}
+
+	// a value that can never fit is not cached and doesn't flush everything else
+	cache.Add("huge", []byte("12345678901"))
+	if _, ok := cache.Get("huge"); ok {
+		t.Errorf("expected oversized value to not be cached")
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Errorf("expected b to survive the oversized add")
+	}
+}
+
+func TestReplaceUpdatesBytes(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Stop()
+	cache.Add("a", []byte("12345"))
+	cache.Add("a", []byte("12"))
+	if stats := cache.Stats(); stats.Bytes != 2 || stats.Entries != 1 {
+		t.Errorf("expected 1 entry using 2 bytes, got %+v", stats)
+	}
+}
+
+func TestStats(t *testing.T) {
+	cache := NewCache(time.Minute, WithMaxEntries(1))
+	defer cache.Stop()
+	cache.Add("a", []byte("1"))
+	cache.Get("a")
+	cache.Get("missing")
+	cache.Add("b", []byte("2"))
+
+	want := Stats{Hits: 1, Misses: 1, Evictions: 1, Entries: 1, Bytes: 1}
+	if got := cache.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestStopEndsReaper(t *testing.T) {
+	before := runtime.NumGoroutine()
+	caches := make([]*Cache, 10)
+	for i := range caches {
+		caches[i] = NewCache(time.Millisecond)
+	}
+	if runtime.NumGoroutine() < before+len(caches) {
+		t.Fatalf("expected a reaper goroutine per cache")
+	}
+	for _, cache := range caches {
+		cache.Stop()
+		// stopping twice must not panic or block
+		cache.Stop()
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected %d goroutines after stopping, got %d", before, after)
+	}
+
+	// the cache keeps working without the reaper
+	caches[0].Add("a", []byte("1"))
+	if _, ok := caches[0].Get("a"); !ok {
+		t.Errorf("expected to find key after stop")
+	}
+}
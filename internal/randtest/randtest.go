@@ -0,0 +1,13 @@
+// Package randtest has the random number generator the game packages are tested with
+package randtest
+
+// Rand hands out the given rolls in order, whatever n is
+type Rand struct {
+	Rolls []int
+}
+
+func (r *Rand) Intn(n int) int {
+	roll := r.Rolls[0]
+	r.Rolls = r.Rolls[1:]
+	return roll
+}
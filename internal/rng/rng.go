@@ -0,0 +1,7 @@
+// Package rng has the source of randomness the game packages roll with
+package rng
+
+// Rand is a source of randomness, *math/rand.Rand satisfies it
+type Rand interface {
+	Intn(n int) int
+}
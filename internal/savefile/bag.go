@@ -0,0 +1,94 @@
+package savefile
+
+import (
+	"errors"
+	"slices"
+)
+
+// the pockets of the bag, in the order it's shown
+const (
+	PocketItems    = "items"
+	PocketMedicine = "medicine"
+	PocketBalls    = "balls"
+	PocketBerries  = "berries"
+	PocketKeyItems = "key-items"
+)
+
+// Pockets lists every pocket of the bag
+var Pockets = []string{PocketItems, PocketMedicine, PocketBalls, PocketBerries, PocketKeyItems}
+
+var (
+	// ErrNoItem is using or giving away more of an item than the bag holds
+	ErrNoItem = errors.New("you don't have enough of that item")
+	// ErrQuantity is putting in or taking out less than one of an item
+	ErrQuantity = errors.New("the quantity has to be at least 1")
+)
+
+// Item is a stack of one kind of item in the bag, Pocket is where it's kept
+type Item struct {
+	Name     string `json:"name"`
+	Pocket   string `json:"pocket"`
+	Quantity int    `json:"quantity"`
+}
+
+// starterBag is what a new game starts out with, like the balls and potions the
+// professor hands out
+func starterBag() []Item {
+	return []Item{
+		{Name: "potion", Pocket: PocketMedicine, Quantity: 3},
+		{Name: "poke-ball", Pocket: PocketBalls, Quantity: 10},
+	}
+}
+
+// ItemCount is how many of an item the bag holds
+func (s *Save) ItemCount(name string) int {
+	if i := s.itemIndex(name); i >= 0 {
+		return s.Bag[i].Quantity
+	}
+	return 0
+}
+
+// AddItem puts quantity of an item in the bag, in pocket unless it's already in one
+func (s *Save) AddItem(name, pocket string, quantity int) error {
+	if quantity < 1 {
+		return ErrQuantity
+	}
+	if i := s.itemIndex(name); i >= 0 {
+		s.Bag[i].Quantity += quantity
+		return nil
+	}
+	s.Bag = append(s.Bag, Item{Name: name, Pocket: pocket, Quantity: quantity})
+	return nil
+}
+
+// RemoveItem takes quantity of an item out of the bag, an item that runs out is gone
+// from its pocket
+func (s *Save) RemoveItem(name string, quantity int) error {
+	if quantity < 1 {
+		return ErrQuantity
+	}
+	i := s.itemIndex(name)
+	if i < 0 || s.Bag[i].Quantity < quantity {
+		return ErrNoItem
+	}
+	s.Bag[i].Quantity -= quantity
+	if s.Bag[i].Quantity == 0 {
+		s.Bag = slices.Delete(s.Bag, i, i+1)
+	}
+	return nil
+}
+
+// Pocket lists the items in a pocket, in the order they were first put in the bag
+func (s *Save) Pocket(pocket string) []Item {
+	var items []Item
+	for _, item := range s.Bag {
+		if item.Pocket == pocket {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (s *Save) itemIndex(name string) int {
+	return slices.IndexFunc(s.Bag, func(item Item) bool { return item.Name == name })
+}
@@ -0,0 +1,58 @@
+package savefile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBag(t *testing.T) {
+	save := New()
+	if got := save.ItemCount("poke-ball"); got != 10 {
+		t.Errorf("expected a new game to start with 10 poke-balls, got %d", got)
+	}
+	save.AddItem("oran-berry", PocketBerries, 2)
+	save.AddItem("great-ball", PocketBalls, 1)
+	save.AddItem("poke-ball", PocketItems, 5)
+	if got := save.ItemCount("poke-ball"); got != 15 {
+		t.Errorf("expected 15 poke-balls, got %d", got)
+	}
+	balls := save.Pocket(PocketBalls)
+	if len(balls) != 2 || balls[0].Name != "poke-ball" || balls[1].Name != "great-ball" {
+		t.Errorf("expected poke-ball and great-ball in the balls pocket, got %+v", balls)
+	}
+
+	if err := save.RemoveItem("oran-berry", 3); !errors.Is(err, ErrNoItem) {
+		t.Errorf("expected ErrNoItem, got %v", err)
+	}
+	if err := save.RemoveItem("oran-berry", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := save.Pocket(PocketBerries); len(got) != 0 {
+		t.Errorf("expected the berries pocket to be empty, got %+v", got)
+	}
+	if err := save.RemoveItem("master-ball", 1); !errors.Is(err, ErrNoItem) {
+		t.Errorf("expected ErrNoItem, got %v", err)
+	}
+
+	for _, quantity := range []int{0, -3} {
+		if err := save.AddItem("potion", PocketMedicine, quantity); !errors.Is(err, ErrQuantity) {
+			t.Errorf("expected ErrQuantity adding %d, got %v", quantity, err)
+		}
+		if err := save.RemoveItem("potion", quantity); !errors.Is(err, ErrQuantity) {
+			t.Errorf("expected ErrQuantity removing %d, got %v", quantity, err)
+		}
+	}
+	if got := save.ItemCount("potion"); got != 3 {
+		t.Errorf("expected the 3 starter potions to be left alone, got %d", got)
+	}
+}
+
+func TestOldSaveGetsStarterBag(t *testing.T) {
+	save, err := decode([]byte(`{"version": 3, "party": [], "boxes": [], "next_id": 1, "pokedex": []}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := save.ItemCount("poke-ball"); got != 10 {
+		t.Errorf("expected a save from before the bag to get 10 poke-balls, got %d", got)
+	}
+}
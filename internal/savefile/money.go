@@ -0,0 +1,28 @@
+package savefile
+
+import "errors"
+
+const (
+	// StartingMoney is what a new game starts out with
+	StartingMoney = 3000
+	// MaxMoney is as much as the player can carry, anything more is lost
+	MaxMoney = 999999
+)
+
+var ErrNotEnoughMoney = errors.New("you don't have enough money")
+
+// Earn adds money, up to MaxMoney, and returns how much was actually added
+func (s *Save) Earn(amount int) int {
+	earned := min(amount, MaxMoney-s.Money)
+	s.Money += earned
+	return earned
+}
+
+// Spend takes money away, but only if there's enough of it
+func (s *Save) Spend(amount int) error {
+	if amount > s.Money {
+		return ErrNotEnoughMoney
+	}
+	s.Money -= amount
+	return nil
+}
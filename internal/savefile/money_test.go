@@ -0,0 +1,22 @@
+package savefile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	save := New()
+	if save.Money != StartingMoney {
+		t.Errorf("expected a new game to start with %d, got %d", StartingMoney, save.Money)
+	}
+	if err := save.Spend(StartingMoney + 1); !errors.Is(err, ErrNotEnoughMoney) {
+		t.Errorf("expected ErrNotEnoughMoney, got %v", err)
+	}
+	if err := save.Spend(1000); err != nil || save.Money != 2000 {
+		t.Errorf("expected 2000 left, got %d (%v)", save.Money, err)
+	}
+	if earned := save.Earn(MaxMoney); earned != MaxMoney-2000 || save.Money != MaxMoney {
+		t.Errorf("expected to earn up to %d, earned %d and have %d", MaxMoney, earned, save.Money)
+	}
+}
@@ -0,0 +1,61 @@
+package savefile
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMoveKnown   = errors.New("it already knows that move")
+	ErrMovesFull   = fmt.Errorf("it already knows %d moves", MaxMoves)
+	ErrMoveUnknown = errors.New("it doesn't know that move")
+	// a pokemon always keeps at least one move to battle with
+	ErrLastMove = errors.New("that's the only move it knows")
+)
+
+// Knows reports whether the pokemon knows the move
+func (p *Pokemon) Knows(name string) bool {
+	return p.moveIndex(name) >= 0
+}
+
+// Learn teaches the pokemon a move, in the place of replacing when that's set
+func (p *Pokemon) Learn(move Move, replacing string) error {
+	if p.Knows(move.Name) {
+		return ErrMoveKnown
+	}
+	if replacing != "" {
+		i := p.moveIndex(replacing)
+		if i < 0 {
+			return ErrMoveUnknown
+		}
+		p.Moves[i] = move
+		return nil
+	}
+	if len(p.Moves) >= MaxMoves {
+		return ErrMovesFull
+	}
+	p.Moves = append(p.Moves, move)
+	return nil
+}
+
+// Forget makes the pokemon forget a move
+func (p *Pokemon) Forget(name string) error {
+	i := p.moveIndex(name)
+	if i < 0 {
+		return ErrMoveUnknown
+	}
+	if len(p.Moves) == 1 {
+		return ErrLastMove
+	}
+	p.Moves = append(p.Moves[:i], p.Moves[i+1:]...)
+	return nil
+}
+
+func (p *Pokemon) moveIndex(name string) int {
+	for i, move := range p.Moves {
+		if move.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,39 @@
+package savefile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLearnAndForget(t *testing.T) {
+	pikachu := Pokemon{Name: "pikachu"}
+	for _, name := range []string{"thunder-shock", "growl", "tail-whip", "thunder-wave"} {
+		if err := pikachu.Learn(Move{Name: name, PP: 10, MaxPP: 10}, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := pikachu.Learn(Move{Name: "growl"}, "tail-whip"); !errors.Is(err, ErrMoveKnown) {
+		t.Errorf("expected ErrMoveKnown, got %v", err)
+	}
+	if err := pikachu.Learn(Move{Name: "quick-attack"}, ""); !errors.Is(err, ErrMovesFull) {
+		t.Errorf("expected ErrMovesFull, got %v", err)
+	}
+	if err := pikachu.Learn(Move{Name: "quick-attack"}, "tail-whip"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pikachu.Moves[2].Name != "quick-attack" || pikachu.Knows("tail-whip") {
+		t.Errorf("expected quick-attack in the place of tail-whip, got %+v", pikachu.Moves)
+	}
+
+	if err := pikachu.Forget("tail-whip"); !errors.Is(err, ErrMoveUnknown) {
+		t.Errorf("expected ErrMoveUnknown, got %v", err)
+	}
+	for _, name := range []string{"growl", "quick-attack", "thunder-wave"} {
+		if err := pikachu.Forget(name); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := pikachu.Forget("thunder-shock"); !errors.Is(err, ErrLastMove) {
+		t.Errorf("expected ErrLastMove, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package savefile
+
+import "sort"
+
+// Register records that a species was seen, or caught. Caught stays set once it is and
+// where and when it was first seen never changes. The pokedex is kept in national dex order,
+// an entry saved before numbers were kept gets its number the next time it's registered
+func (s *Save) Register(entry DexEntry) {
+	i := s.dexIndex(entry)
+	if i < 0 {
+		s.Pokedex = append(s.Pokedex, entry)
+	} else {
+		existing := &s.Pokedex[i]
+		existing.Number = max(existing.Number, entry.Number)
+		existing.Caught = existing.Caught || entry.Caught
+		if existing.FirstSeenAt == nil {
+			existing.FirstSeenIn, existing.FirstSeenAt = entry.FirstSeenIn, entry.FirstSeenAt
+		}
+	}
+	// entries that don't have a number yet go last
+	sort.SliceStable(s.Pokedex, func(i, j int) bool {
+		a, b := s.Pokedex[i].Number, s.Pokedex[j].Number
+		return a != 0 && (b == 0 || a < b)
+	})
+}
+
+// Registered looks a species up in the pokedex by national dex number
+func (s *Save) Registered(number int) (DexEntry, bool) {
+	for _, entry := range s.Pokedex {
+		if entry.Number == number {
+			return entry, true
+		}
+	}
+	return DexEntry{}, false
+}
+
+// dexIndex finds the entry for the same species, by number or by name for entries that
+// don't have one yet
+func (s *Save) dexIndex(entry DexEntry) int {
+	for i, existing := range s.Pokedex {
+		if entry.Number != 0 && existing.Number == entry.Number {
+			return i
+		}
+		if existing.Name == entry.Name && (existing.Number == 0 || entry.Number == 0) {
+			return i
+		}
+	}
+	return -1
+}
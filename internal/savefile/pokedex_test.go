@@ -0,0 +1,39 @@
+package savefile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegister(t *testing.T) {
+	save := New()
+	morning := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+	evening := morning.Add(12 * time.Hour)
+	// a species from a save written before dex numbers were kept
+	save.Pokedex = append(save.Pokedex, DexEntry{Name: "tentacool", Caught: true}, DexEntry{Name: "eevee"})
+
+	save.Register(DexEntry{Number: 25, Name: "pikachu", FirstSeenIn: "canalave-city-area", FirstSeenAt: &morning})
+	save.Register(DexEntry{Number: 25, Name: "pikachu", Caught: true, FirstSeenIn: "eterna-city-area", FirstSeenAt: &evening})
+	save.Register(DexEntry{Number: 1, Name: "bulbasaur", FirstSeenAt: &evening})
+	save.Register(DexEntry{Number: 72, Name: "tentacool", FirstSeenAt: &evening})
+
+	if len(save.Pokedex) != 4 {
+		t.Fatalf("expected 4 species, got %+v", save.Pokedex)
+	}
+	// eevee's number isn't known yet, so it goes last
+	for i, number := range []int{1, 25, 72, 0} {
+		if save.Pokedex[i].Number != number {
+			t.Errorf("expected #%d in place %d, got %+v", number, i, save.Pokedex[i])
+		}
+	}
+	pikachu, ok := save.Registered(25)
+	if !ok || !pikachu.Caught || pikachu.FirstSeenIn != "canalave-city-area" || !pikachu.FirstSeenAt.Equal(morning) {
+		t.Errorf("expected pikachu caught and first seen in the morning in canalave, got %+v", pikachu)
+	}
+	if tentacool, _ := save.Registered(72); !tentacool.Caught {
+		t.Errorf("expected the old tentacool entry to get its number and stay caught, got %+v", tentacool)
+	}
+	if _, ok := save.Registered(150); ok {
+		t.Error("expected mewtwo not to be registered")
+	}
+}
@@ -0,0 +1,311 @@
+package savefile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/staf3333/pokedexcli/internal/stats"
+)
+
+// CurrentVersion is the schema version written by this build. Bump it whenever the layout of
+// Save changes in a way old files can't be read as-is, and add a migration for the old version
+const CurrentVersion = 3
+
+// Save is everything that survives between sessions
+// we only keep what the commands actually need instead of the whole raw API response
+type Save struct {
+	Version int `json:"version"`
+	// every caught pokemon is either in the party or in one of the PC boxes
+	Party []Pokemon   `json:"party"`
+	Boxes [][]Pokemon `json:"boxes"`
+	// NextID is the ID the next caught pokemon gets, IDs are never reused
+	NextID  int        `json:"next_id"`
+	Pokedex []DexEntry `json:"pokedex"`
+	// GameVersion is the version group (e.g. red-blue) the game is played as,
+	// empty means anything from any game goes
+	GameVersion string `json:"game_version,omitempty"`
+	// Position is where the player is, all empty before traveling anywhere
+	Position Position `json:"position"`
+	// Bag holds the player's items, saves from before there were items get the starter bag
+	Bag []Item `json:"bag"`
+	// Money is what the player has to spend at the shop, saves from before there was money
+	// get StartingMoney
+	Money int `json:"money"`
+}
+
+// Position is the player's place in the world, from the largest to the smallest
+type Position struct {
+	Region   string `json:"region,omitempty"`
+	Location string `json:"location,omitempty"`
+	Area     string `json:"area,omitempty"`
+}
+
+// Pokemon is a snapshot of a caught pokemon taken at catch time. ID tells apart
+// pokemon of the same species
+type Pokemon struct {
+	ID        int       `json:"id"`
+	SpeciesID int       `json:"species_id"`
+	Name      string    `json:"name"`
+	CaughtAt  time.Time `json:"caught_at"`
+	Height    int       `json:"height"`
+	Weight    int       `json:"weight"`
+	Stats     []Stat    `json:"stats"`
+	Types     []string  `json:"types"`
+	// Sprite is the default front sprite, saves from before it was kept don't have one
+	Sprite string `json:"sprite,omitempty"`
+
+	// what makes this pokemon different from others of its species, decided when it was
+	// found. IVs and EVs are keyed by stat name
+	Level  int            `json:"level"`
+	Nature stats.Nature   `json:"nature"`
+	IVs    map[string]int `json:"ivs"`
+	EVs    map[string]int `json:"evs"`
+	// Gender is "male", "female" or "" for species without one
+	Gender string `json:"gender,omitempty"`
+	Shiny  bool   `json:"shiny,omitempty"`
+	// Ability is the one of its species' abilities the pokemon has, pokemon saved before
+	// abilities were kept have none
+	Ability string `json:"ability,omitempty"`
+	// HeldItem is the item the pokemon is holding, some species evolve with the right one
+	HeldItem string `json:"held_item,omitempty"`
+	// Moves are the up to MaxMoves moves the pokemon knows, pokemon saved before movesets
+	// were kept have none
+	Moves []Move `json:"moves,omitempty"`
+	// Damage is the HP the pokemon has lost, 0 at full health. Keeping the damage rather
+	// than the HP left means pokemon saved before battles existed are simply healthy
+	Damage int `json:"damage,omitempty"`
+}
+
+// DexEntry is a species the player has come across, Caught once one has been caught.
+// Number is its national dex number, 0 for entries saved before it was kept
+type DexEntry struct {
+	Number int    `json:"number,omitempty"`
+	Name   string `json:"name"`
+	Caught bool   `json:"caught"`
+	// where and when the species was first seen, unknown for entries saved before it was kept
+	FirstSeenIn string     `json:"first_seen_in,omitempty"`
+	FirstSeenAt *time.Time `json:"first_seen_at,omitempty"`
+}
+
+// MaxMoves is how many moves a pokemon can know at once
+const MaxMoves = 4
+
+// Move is a move a pokemon knows, PP is how many more times it can be used before resting
+type Move struct {
+	Name  string `json:"name"`
+	PP    int    `json:"pp"`
+	MaxPP int    `json:"max_pp"`
+}
+
+type Stat struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+// migration upgrades the raw fields of a save file from one version to the next
+// working on raw JSON means a migration never depends on the current Go types
+type migration func(fields map[string]json.RawMessage) error
+
+// migrations[v] upgrades a version v file to version v+1
+var migrations = map[int]migration{
+	1: migrateToParty,
+	2: migrateToInstances,
+}
+
+// migrateToInstances gives pokemon caught before they had levels and natures the values
+// of a freshly hatched one: level 5, a neutral nature, no IVs or EVs
+func migrateToInstances(fields map[string]json.RawMessage) error {
+	upgrade := func(p map[string]json.RawMessage) {
+		p["level"] = json.RawMessage(`5`)
+		p["nature"] = json.RawMessage(`{"name": "hardy"}`)
+		p["ivs"] = json.RawMessage(`{}`)
+		p["evs"] = json.RawMessage(`{}`)
+	}
+
+	var party []map[string]json.RawMessage
+	if err := json.Unmarshal(fields["party"], &party); err != nil {
+		return fmt.Errorf("reading party: %w", err)
+	}
+	var boxes [][]map[string]json.RawMessage
+	if err := json.Unmarshal(fields["boxes"], &boxes); err != nil {
+		return fmt.Errorf("reading boxes: %w", err)
+	}
+	for _, p := range party {
+		upgrade(p)
+	}
+	for _, box := range boxes {
+		for _, p := range box {
+			upgrade(p)
+		}
+	}
+
+	var err error
+	if fields["party"], err = json.Marshal(party); err != nil {
+		return err
+	}
+	fields["boxes"], err = json.Marshal(boxes)
+	return err
+}
+
+func New() *Save {
+	return &Save{
+		Version: CurrentVersion,
+		Party:   []Pokemon{},
+		Boxes:   emptyBoxes(),
+		NextID:  1,
+		Pokedex: []DexEntry{},
+		Bag:     starterBag(),
+		Money:   StartingMoney,
+	}
+}
+
+// migrateToParty moves the version 1 list of pokemon, one per species, into the party and
+// then the first box, giving each an ID, and registers them all as caught
+func migrateToParty(fields map[string]json.RawMessage) error {
+	var pokemon []map[string]json.RawMessage
+	if raw, ok := fields["pokemon"]; ok {
+		if err := json.Unmarshal(raw, &pokemon); err != nil {
+			return fmt.Errorf("reading pokemon: %w", err)
+		}
+	}
+	party := []map[string]json.RawMessage{}
+	boxes := [][]map[string]json.RawMessage{}
+	pokedex := []DexEntry{}
+	for i, p := range pokemon {
+		p["id"] = json.RawMessage(fmt.Sprint(i + 1))
+		if len(party) < PartySize {
+			party = append(party, p)
+		} else {
+			// boxes are added as they fill up, decode pads out the rest
+			box := (i - PartySize) / BoxSize
+			for len(boxes) <= box {
+				boxes = append(boxes, []map[string]json.RawMessage{})
+			}
+			boxes[box] = append(boxes[box], p)
+		}
+		var name string
+		if err := json.Unmarshal(p["name"], &name); err != nil {
+			return fmt.Errorf("reading pokemon name: %w", err)
+		}
+		pokedex = append(pokedex, DexEntry{Name: name, Caught: true})
+	}
+
+	for key, value := range map[string]any{"party": party, "boxes": boxes, "next_id": len(pokemon) + 1, "pokedex": pokedex} {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fields[key] = raw
+	}
+	delete(fields, "pokemon")
+	return nil
+}
+
+// DefaultPath is where the save file lives unless told otherwise:
+// $XDG_DATA_HOME/pokedexcli/save.json, falling back to ~/.local/share like the XDG spec says
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("savefile: finding data directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "pokedexcli", "save.json"), nil
+}
+
+// Load reads the save file at path, migrating it to CurrentVersion if it is older.
+// A missing file is reported with an error matching os.ErrNotExist
+func Load(path string) (*Save, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("savefile: %w", err)
+	}
+	save, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("savefile: %s: %w", path, err)
+	}
+	return save, nil
+}
+
+func decode(data []byte) (*Save, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	version := 0
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("reading version: %w", err)
+		}
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("save file is version %d but this build only understands up to %d", version, CurrentVersion)
+	}
+
+	for ; version < CurrentVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration from version %d", version)
+		}
+		if err := migrate(fields); err != nil {
+			return nil, fmt.Errorf("migrating from version %d: %w", version, err)
+		}
+		fields["version"] = json.RawMessage(fmt.Sprint(version + 1))
+	}
+
+	upgraded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	save := New()
+	if err := json.Unmarshal(upgraded, save); err != nil {
+		return nil, err
+	}
+	// a file written with fewer boxes (or hand edited) still gets all of them
+	for len(save.Boxes) < BoxCount {
+		save.Boxes = append(save.Boxes, []Pokemon{})
+	}
+	return save, nil
+}
+
+// Write saves to path atomically: the data goes to a temp file in the same directory which is
+// then renamed over the old save, so a crash halfway through never leaves a truncated file
+func (s *Save) Write(path string) error {
+	s.Version = CurrentVersion
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("savefile: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("savefile: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("savefile: %w", err)
+	}
+	// if anything below fails, don't leave the temp file lying around
+	// (after a successful rename this is a no-op)
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("savefile: writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("savefile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package savefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "save.json")
+	save := New()
+	save.Catch(Pokemon{
+		SpeciesID: 25,
+		Name:      "pikachu",
+		CaughtAt:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Stats:     []Stat{{Name: "hp", BaseStat: 35}},
+		Types:     []string{"electric"},
+	})
+	if err := save.Write(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pokemon, ok := loaded.Find("pikachu")
+	if !ok {
+		t.Fatalf("expected to find pikachu")
+	}
+	if pokemon.ID != 1 || pokemon.SpeciesID != 25 || !pokemon.CaughtAt.Equal(save.Party[0].CaughtAt) {
+		t.Errorf("unexpected pokemon %+v", pokemon)
+	}
+
+	// the temp file used for the atomic write should be gone
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the save file in the directory, got %d entries", len(entries))
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "save.json"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
+
+func TestLoadNewerVersion(t *testing.T) {
+	_, err := decode([]byte(`{"version": 999, "pokemon": []}`))
+	if err == nil {
+		t.Errorf("expected an error for a save from a newer build")
+	}
+}
+
+func TestMigrateFromVersion1(t *testing.T) {
+	data := `{"version": 1, "pokemon": [
+		{"name": "bulbasaur"}, {"name": "ivysaur"}, {"name": "venusaur"}, {"name": "charmander"},
+		{"name": "charmeleon"}, {"name": "charizard"}, {"name": "squirtle"}
+	]}`
+	save, err := decode([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(save.Party) != PartySize || len(save.Boxes) != BoxCount || len(save.Boxes[0]) != 1 {
+		t.Fatalf("expected a full party and one pokemon in box 1, got %+v", save)
+	}
+	if save.Boxes[0][0].Name != "squirtle" || save.Boxes[0][0].ID != 7 || save.NextID != 8 {
+		t.Errorf("unexpected box %+v (next ID %d)", save.Boxes[0], save.NextID)
+	}
+	if len(save.Pokedex) != 7 || !save.Pokedex[6].Caught {
+		t.Errorf("expected every pokemon registered as caught, got %+v", save.Pokedex)
+	}
+	if p := save.Boxes[0][0]; p.Level != 5 || p.Nature.Name != "hardy" || p.IVs == nil {
+		t.Errorf("expected old pokemon to become level 5 with a neutral nature, got %+v", p)
+	}
+}
@@ -0,0 +1,226 @@
+package savefile
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// PartySize is how many pokemon travel with the player
+	PartySize = 6
+	// BoxCount and BoxSize are how many PC boxes a new game has and how much fits in one
+	BoxCount = 8
+	BoxSize  = 30
+)
+
+var (
+	ErrNoPokemon   = errors.New("no pokemon with that ID")
+	ErrPartyFull   = errors.New("your party is full")
+	ErrBoxFull     = errors.New("that box is full")
+	ErrStorageFull = errors.New("your party and every box are full")
+	// the player always keeps at least one pokemon with them
+	ErrLastInParty = errors.New("that's the last pokemon in your party")
+)
+
+func emptyBoxes() [][]Pokemon {
+	boxes := make([][]Pokemon, BoxCount)
+	for i := range boxes {
+		boxes[i] = []Pokemon{}
+	}
+	return boxes
+}
+
+// Place is where a pokemon is kept: Box 0 is the party, boxes are numbered from 1
+type Place struct {
+	Box  int
+	Slot int
+}
+
+func (p Place) String() string {
+	if p.Box == 0 {
+		return "your party"
+	}
+	return fmt.Sprintf("box %d", p.Box)
+}
+
+// Catch stores a newly caught pokemon, giving it an ID: in the party if there's room,
+// otherwise in the first box that has some
+func (s *Save) Catch(p Pokemon) (Pokemon, Place, error) {
+	place := Place{}
+	if len(s.Party) >= PartySize {
+		box, ok := s.boxWithRoom()
+		if !ok {
+			return Pokemon{}, Place{}, ErrStorageFull
+		}
+		place.Box = box
+	}
+	p.ID = s.NextID
+	s.NextID++
+	s.put(p, place.Box)
+	caughtAt := p.CaughtAt
+	s.Register(DexEntry{Number: p.SpeciesID, Name: p.Name, Caught: true, FirstSeenAt: &caughtAt})
+	place.Slot = len(*s.list(place.Box)) - 1
+	return p, place, nil
+}
+
+// HasRoom reports whether there's anywhere to put another pokemon
+func (s *Save) HasRoom() bool {
+	_, ok := s.boxWithRoom()
+	return len(s.Party) < PartySize || ok
+}
+
+// Get looks up a caught pokemon by ID
+func (s *Save) Get(id int) (Pokemon, Place, bool) {
+	place, ok := s.find(id)
+	if !ok {
+		return Pokemon{}, Place{}, false
+	}
+	return (*s.list(place.Box))[place.Slot], place, true
+}
+
+// Update replaces the caught pokemon that has p's ID with p
+func (s *Save) Update(p Pokemon) error {
+	place, ok := s.find(p.ID)
+	if !ok {
+		return ErrNoPokemon
+	}
+	(*s.list(place.Box))[place.Slot] = p
+	return nil
+}
+
+// Heal restores every caught pokemon to full health and the PP of all their moves
+func (s *Save) Heal() {
+	for box := 0; box <= len(s.Boxes); box++ {
+		for i := range *s.list(box) {
+			p := &(*s.list(box))[i]
+			p.Damage = 0
+			for j := range p.Moves {
+				p.Moves[j].PP = p.Moves[j].MaxPP
+			}
+		}
+	}
+}
+
+// Find looks up the first caught pokemon of a species, party first
+func (s *Save) Find(name string) (Pokemon, bool) {
+	for box := 0; box <= len(s.Boxes); box++ {
+		for _, p := range *s.list(box) {
+			if p.Name == name {
+				return p, true
+			}
+		}
+	}
+	return Pokemon{}, false
+}
+
+// Count is how many pokemon have been caught and kept
+func (s *Save) Count() int {
+	count := len(s.Party)
+	for _, box := range s.Boxes {
+		count += len(box)
+	}
+	return count
+}
+
+// Deposit moves a party pokemon into box, or into the first box with room when box is 0
+func (s *Save) Deposit(id, box int) (Place, error) {
+	place, ok := s.find(id)
+	switch {
+	case !ok:
+		return Place{}, ErrNoPokemon
+	case place.Box != 0:
+		return Place{}, fmt.Errorf("that pokemon is already in %s", place)
+	case len(s.Party) == 1:
+		return Place{}, ErrLastInParty
+	case box < 0 || box > len(s.Boxes):
+		return Place{}, fmt.Errorf("there's no box %d, boxes go from 1 to %d", box, len(s.Boxes))
+	}
+	if box == 0 {
+		if box, ok = s.boxWithRoom(); !ok {
+			return Place{}, ErrStorageFull
+		}
+	} else if len(s.Boxes[box-1]) >= BoxSize {
+		return Place{}, ErrBoxFull
+	}
+	s.put(s.take(place), box)
+	return Place{Box: box, Slot: len(s.Boxes[box-1]) - 1}, nil
+}
+
+// Withdraw moves a boxed pokemon into the party
+func (s *Save) Withdraw(id int) (Place, error) {
+	place, ok := s.find(id)
+	switch {
+	case !ok:
+		return Place{}, ErrNoPokemon
+	case place.Box == 0:
+		return Place{}, errors.New("that pokemon is already in your party")
+	case len(s.Party) >= PartySize:
+		return Place{}, ErrPartyFull
+	}
+	s.put(s.take(place), 0)
+	return Place{Slot: len(s.Party) - 1}, nil
+}
+
+// Swap trades the places of two pokemon, wherever they are
+func (s *Save) Swap(a, b int) error {
+	placeA, okA := s.find(a)
+	placeB, okB := s.find(b)
+	if !okA || !okB {
+		return ErrNoPokemon
+	}
+	listA, listB := *s.list(placeA.Box), *s.list(placeB.Box)
+	listA[placeA.Slot], listB[placeB.Slot] = listB[placeB.Slot], listA[placeA.Slot]
+	return nil
+}
+
+// Release lets a pokemon go for good, the pokedex still remembers catching it
+func (s *Save) Release(id int) (Pokemon, error) {
+	place, ok := s.find(id)
+	switch {
+	case !ok:
+		return Pokemon{}, ErrNoPokemon
+	case place.Box == 0 && len(s.Party) == 1:
+		return Pokemon{}, ErrLastInParty
+	}
+	return s.take(place), nil
+}
+
+// list is the party for box 0, otherwise the box with that number
+func (s *Save) list(box int) *[]Pokemon {
+	if box == 0 {
+		return &s.Party
+	}
+	return &s.Boxes[box-1]
+}
+
+func (s *Save) find(id int) (Place, bool) {
+	for box := 0; box <= len(s.Boxes); box++ {
+		for slot, p := range *s.list(box) {
+			if p.ID == id {
+				return Place{Box: box, Slot: slot}, true
+			}
+		}
+	}
+	return Place{}, false
+}
+
+func (s *Save) boxWithRoom() (int, bool) {
+	for i, box := range s.Boxes {
+		if len(box) < BoxSize {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Save) put(p Pokemon, box int) {
+	list := s.list(box)
+	*list = append(*list, p)
+}
+
+func (s *Save) take(place Place) Pokemon {
+	list := s.list(place.Box)
+	p := (*list)[place.Slot]
+	*list = append((*list)[:place.Slot], (*list)[place.Slot+1:]...)
+	return p
+}
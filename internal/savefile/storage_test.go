@@ -0,0 +1,114 @@
+package savefile
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestSave catches count pikachu, IDs 1 to count
+func newTestSave(t *testing.T, count int) *Save {
+	t.Helper()
+	save := New()
+	for i := 0; i < count; i++ {
+		if _, _, err := save.Catch(Pokemon{Name: "pikachu"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return save
+}
+
+func TestCatchKeepsEveryPokemon(t *testing.T) {
+	save := newTestSave(t, 2)
+	if len(save.Party) != 2 || save.Party[0].ID == save.Party[1].ID {
+		t.Errorf("expected two pikachu with their own IDs, got %+v", save.Party)
+	}
+	if len(save.Pokedex) != 1 || !save.Pokedex[0].Caught {
+		t.Errorf("expected one caught species in the pokedex, got %+v", save.Pokedex)
+	}
+}
+
+func TestCatchFillsBoxesWhenPartyIsFull(t *testing.T) {
+	save := newTestSave(t, PartySize)
+	p, place, err := save.Catch(Pokemon{Name: "eevee"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if place != (Place{Box: 1, Slot: 0}) || p.ID != PartySize+1 {
+		t.Errorf("expected eevee in box 1, got %+v at %+v", p, place)
+	}
+
+	save = New()
+	save.Party = make([]Pokemon, PartySize)
+	for i := range save.Boxes {
+		save.Boxes[i] = make([]Pokemon, BoxSize)
+	}
+	if _, _, err := save.Catch(Pokemon{Name: "eevee"}); !errors.Is(err, ErrStorageFull) {
+		t.Errorf("expected ErrStorageFull, got %v", err)
+	}
+}
+
+func TestDepositWithdraw(t *testing.T) {
+	save := newTestSave(t, 2)
+	if _, err := save.Deposit(1, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, place, _ := save.Get(1); place != (Place{Box: 3}) {
+		t.Errorf("expected pokemon 1 in box 3, got %+v", place)
+	}
+	if _, err := save.Deposit(2, 0); !errors.Is(err, ErrLastInParty) {
+		t.Errorf("expected ErrLastInParty, got %v", err)
+	}
+	if _, err := save.Deposit(99, 0); !errors.Is(err, ErrNoPokemon) {
+		t.Errorf("expected ErrNoPokemon, got %v", err)
+	}
+
+	if _, err := save.Withdraw(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(save.Party) != 2 || save.Party[1].ID != 1 {
+		t.Errorf("expected pokemon 1 back at the end of the party, got %+v", save.Party)
+	}
+}
+
+func TestSwapAndRelease(t *testing.T) {
+	save := newTestSave(t, 3)
+	save.Deposit(3, 1)
+	if err := save.Swap(1, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if save.Party[0].ID != 3 || save.Boxes[0][0].ID != 1 {
+		t.Errorf("expected 1 and 3 to trade places, got party %+v box %+v", save.Party, save.Boxes[0])
+	}
+
+	if _, err := save.Release(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, ok := save.Get(1); ok || save.Count() != 2 {
+		t.Errorf("expected pokemon 1 to be gone")
+	}
+	save.Release(2)
+	if _, err := save.Release(3); !errors.Is(err, ErrLastInParty) {
+		t.Errorf("expected ErrLastInParty, got %v", err)
+	}
+}
+
+func TestUpdateAndHeal(t *testing.T) {
+	save := newTestSave(t, 2)
+	hurt := save.Party[1]
+	hurt.Damage = 12
+	hurt.Moves = []Move{{Name: "thunder-shock", PP: 3, MaxPP: 30}}
+	if err := save.Update(hurt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual, _, _ := save.Get(hurt.ID); actual.Damage != 12 {
+		t.Errorf("expected the damage to be kept, got %+v", actual)
+	}
+	if err := save.Update(Pokemon{ID: 99}); !errors.Is(err, ErrNoPokemon) {
+		t.Errorf("expected ErrNoPokemon, got %v", err)
+	}
+
+	save.Heal()
+	if healed, _, _ := save.Get(hurt.ID); healed.Damage != 0 || healed.Moves[0].PP != 30 {
+		t.Errorf("expected full HP and PP, got %+v", healed)
+	}
+}
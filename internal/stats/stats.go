@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"math"
+
+	"github.com/staf3333/pokedexcli/internal/rng"
+)
+
+// Names are the six stats in the order the games (and the PokeAPI) list them
+var Names = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+const (
+	// MaxIV is the highest individual value a stat can have, they're rolled from 0 to MaxIV
+	MaxIV = 31
+	// MaxEV and MaxTotalEV cap the effort values of one stat and of all of them together
+	MaxEV      = 252
+	MaxTotalEV = 510
+	// ShinyOdds is the 1 in ShinyOdds chance of a pokemon being shiny
+	ShinyOdds = 4096
+	// MaxLevel is as far as a pokemon can grow
+	MaxLevel = 100
+)
+
+// Nature raises one stat by 10% and lowers another by 10%. Neutral natures (hardy, docile, ...)
+// leave both empty, or name the same stat twice
+type Nature struct {
+	Name      string `json:"name"`
+	Increased string `json:"increased,omitempty"`
+	Decreased string `json:"decreased,omitempty"`
+}
+
+// Modifier is what the nature multiplies stat by
+func (n Nature) Modifier(stat string) float64 {
+	switch {
+	case n.Increased == n.Decreased:
+		return 1
+	case stat == n.Increased:
+		return 1.1
+	case stat == n.Decreased:
+		return 0.9
+	}
+	return 1
+}
+
+// Compute is the mainline (Gen III onwards) stat formula:
+//
+//	hp    = (2*base + iv + ev/4) * level / 100 + level + 10
+//	other = ((2*base + iv + ev/4) * level / 100 + 5) * nature
+//
+// rounding down at every step like the games do
+func Compute(stat string, base, iv, ev, level int, nature Nature) int {
+	core := (2*base + iv + ev/4) * level / 100
+	if stat == "hp" {
+		return core + level + 10
+	}
+	return int(math.Floor(float64(core+5) * nature.Modifier(stat)))
+}
+
+// RollIVs picks an individual value from 0 to MaxIV for each stat
+func RollIVs(rng rng.Rand) map[string]int {
+	ivs := make(map[string]int, len(Names))
+	for _, stat := range Names {
+		ivs[stat] = rng.Intn(MaxIV + 1)
+	}
+	return ivs
+}
+
+// RollGender picks a gender from the species' gender rate: the chance of being female in
+// eighths, or -1 for species without a gender. Genderless pokemon get ""
+func RollGender(rng rng.Rand, genderRate int) string {
+	switch {
+	case genderRate < 0:
+		return ""
+	case rng.Intn(8) < genderRate:
+		return "female"
+	}
+	return "male"
+}
+
+// RollShiny is true once in ShinyOdds
+func RollShiny(rng rng.Rand) bool {
+	return rng.Intn(ShinyOdds) == 0
+}
@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/staf3333/pokedexcli/internal/randtest"
+)
+
+func TestCompute(t *testing.T) {
+	adamant := Nature{Name: "adamant", Increased: "attack", Decreased: "special-attack"}
+	// garchomp at level 78 from the stat formula's well known worked example
+	cases := []struct {
+		stat     string
+		base     int
+		iv       int
+		ev       int
+		expected int
+	}{
+		{stat: "hp", base: 108, iv: 24, ev: 74, expected: 289},
+		{stat: "attack", base: 130, iv: 12, ev: 190, expected: 278},
+		{stat: "defense", base: 95, iv: 30, ev: 91, expected: 193},
+		{stat: "special-attack", base: 80, iv: 16, ev: 48, expected: 135},
+		{stat: "special-defense", base: 85, iv: 23, ev: 84, expected: 171},
+		{stat: "speed", base: 102, iv: 5, ev: 23, expected: 171},
+	}
+
+	for _, c := range cases {
+		t.Run(c.stat, func(t *testing.T) {
+			if actual := Compute(c.stat, c.base, c.iv, c.ev, 78, adamant); actual != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNeutralNature(t *testing.T) {
+	hardy := Nature{Name: "hardy", Increased: "attack", Decreased: "attack"}
+	if modifier := hardy.Modifier("attack"); modifier != 1 {
+		t.Errorf("expected a neutral nature to leave attack alone, got %v", modifier)
+	}
+	if modifier := (Nature{Name: "docile"}).Modifier("speed"); modifier != 1 {
+		t.Errorf("expected a nature without stats to leave speed alone, got %v", modifier)
+	}
+}
+
+func TestRollGender(t *testing.T) {
+	cases := []struct {
+		name       string
+		genderRate int
+		roll       int
+		expected   string
+	}{
+		{name: "genderless", genderRate: -1, expected: ""},
+		{name: "always male", genderRate: 0, roll: 0, expected: "male"},
+		{name: "always female", genderRate: 8, roll: 7, expected: "female"},
+		{name: "one in eight female, rolled female", genderRate: 1, roll: 0, expected: "female"},
+		{name: "one in eight female, rolled male", genderRate: 1, roll: 1, expected: "male"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := RollGender(&randtest.Rand{Rolls: []int{c.roll}}, c.genderRate); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package typechart
+
+// Names are the 18 types in the order the PokeAPI numbers them
+var Names = []string{
+	"normal", "fighting", "flying", "poison", "ground", "rock", "bug", "ghost", "steel",
+	"fire", "water", "grass", "electric", "psychic", "ice", "dragon", "dark", "fairy",
+}
+
+// Relations are the damage relations of one attacking type, by defending type name
+type Relations struct {
+	DoubleDamageTo []string
+	HalfDamageTo   []string
+	NoDamageTo     []string
+}
+
+// Chart is the type effectiveness table, built up from the relations of each attacking type.
+// A type that hasn't been added is treated as neutral against everything
+type Chart map[string]map[string]float64
+
+// Add records the relations of the attacking type
+func (c Chart) Add(attacking string, relations Relations) {
+	row := map[string]float64{}
+	for _, defending := range relations.DoubleDamageTo {
+		row[defending] = 2
+	}
+	for _, defending := range relations.HalfDamageTo {
+		row[defending] = 0.5
+	}
+	for _, defending := range relations.NoDamageTo {
+		row[defending] = 0
+	}
+	c[attacking] = row
+}
+
+// Has reports whether the relations of the attacking type have been added
+func (c Chart) Has(attacking string) bool {
+	_, ok := c[attacking]
+	return ok
+}
+
+// Complete reports whether the relations of every type in Names have been added
+func (c Chart) Complete() bool {
+	for _, name := range Names {
+		if !c.Has(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Multiplier is how effective a move of the attacking type is against a pokemon of the
+// defending types, the multipliers of dual types stack: 0, 0.25, 0.5, 1, 2 or 4
+func (c Chart) Multiplier(attacking string, defending ...string) float64 {
+	multiplier := 1.0
+	for _, defendingType := range defending {
+		if m, ok := c[attacking][defendingType]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// Defense is how effective each type in Names is against a pokemon of the defending types
+func (c Chart) Defense(defending ...string) map[string]float64 {
+	defense := make(map[string]float64, len(Names))
+	for _, attacking := range Names {
+		defense[attacking] = c.Multiplier(attacking, defending...)
+	}
+	return defense
+}
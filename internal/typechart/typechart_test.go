@@ -0,0 +1,56 @@
+package typechart
+
+import "testing"
+
+func TestMultiplier(t *testing.T) {
+	chart := Chart{}
+	chart.Add("electric", Relations{
+		DoubleDamageTo: []string{"flying", "water"},
+		HalfDamageTo:   []string{"dragon", "electric", "grass"},
+		NoDamageTo:     []string{"ground"},
+	})
+
+	cases := []struct {
+		name      string
+		attacking string
+		defending []string
+		expected  float64
+	}{
+		{name: "super effective", attacking: "electric", defending: []string{"water"}, expected: 2},
+		{name: "both types weak", attacking: "electric", defending: []string{"water", "flying"}, expected: 4},
+		{name: "weak and resistant", attacking: "electric", defending: []string{"water", "dragon"}, expected: 1},
+		{name: "immune", attacking: "electric", defending: []string{"ground", "water"}, expected: 0},
+		{name: "neutral", attacking: "electric", defending: []string{"normal"}, expected: 1},
+		{name: "unknown attacking type", attacking: "fire", defending: []string{"grass"}, expected: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := chart.Multiplier(c.attacking, c.defending...); actual != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDefense(t *testing.T) {
+	chart := Chart{}
+	chart.Add("electric", Relations{DoubleDamageTo: []string{"flying", "water"}, NoDamageTo: []string{"ground"}})
+	chart.Add("grass", Relations{DoubleDamageTo: []string{"water", "ground"}, HalfDamageTo: []string{"flying"}})
+	chart.Add("ice", Relations{DoubleDamageTo: []string{"flying", "ground"}})
+
+	// gyarados is water/flying, swampert is water/ground
+	gyarados, swampert := chart.Defense("water", "flying"), chart.Defense("water", "ground")
+	if gyarados["electric"] != 4 || gyarados["grass"] != 1 || gyarados["ice"] != 2 {
+		t.Errorf("unexpected defense for water/flying: %v", gyarados)
+	}
+	if swampert["electric"] != 0 || swampert["grass"] != 4 || swampert["fire"] != 1 {
+		t.Errorf("unexpected defense for water/ground: %v", swampert)
+	}
+	if len(swampert) != len(Names) {
+		t.Errorf("expected a multiplier for each of the %d types, got %d", len(Names), len(swampert))
+	}
+	if chart.Complete() {
+		t.Errorf("expected a chart with 3 types not to be complete")
+	}
+}
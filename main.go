@@ -1,17 +1,29 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log"
 	"math/rand"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/staf3333/pokedexcli/internal/battle"
+	"github.com/staf3333/pokedexcli/internal/catch"
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/output"
 	"github.com/staf3333/pokedexcli/internal/pokeapi"
 	"github.com/staf3333/pokedexcli/internal/pokecache"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+	"github.com/staf3333/pokedexcli/internal/stats"
+	"github.com/staf3333/pokedexcli/internal/typechart"
 )
 
 // main will be the thing that actually runs the command
@@ -19,10 +31,56 @@ import (
 // hint below
 // the below block of code creates a new struct called cliCommand which
 // we will we will map to string value that we read in from the buffer
+// the same definitions serve the REPL and running a command straight from the shell
+// (pokedexcli explore canalave-city-area), so both check arguments and flags the same way
+// minArgs and maxArgs are how many positional arguments the command takes
 type cliCommand struct {
 	name        string
+	usage       string
 	description string
-	callback    func(*config, ...string) error
+	minArgs     int
+	maxArgs     int
+	flags       []commandFlag
+	// notInBattle commands can't be used while battling, e.g. wandering off with travel
+	notInBattle bool
+	callback    func(context.Context, *config, cmdline.Command) (any, error)
+}
+
+// commandFlag is a --flag a command accepts. Flags that take a value are given as
+// --page 3 or --page=3, the others are switches like --json
+type commandFlag struct {
+	name       string
+	usage      string
+	takesValue bool
+}
+
+// globalFlags are accepted by every command on top of its own flags
+var globalFlags = []commandFlag{
+	{name: "output", usage: "show the result as " + strings.Join(output.Names(), ", "), takesValue: true},
+	{name: "json", usage: "same as --output json"},
+}
+
+// allFlags is the command's own flags plus the global ones
+func (c cliCommand) allFlags() []commandFlag {
+	return append(append([]commandFlag{}, c.flags...), globalFlags...)
+}
+
+// valueFlags lists the flags that take a value, which is what the tokenizer needs to know
+// to read --page 3 correctly
+func (c cliCommand) valueFlags() map[string]bool {
+	valueFlags := map[string]bool{}
+	for _, flag := range c.allFlags() {
+		if flag.takesValue {
+			valueFlags[flag.name] = true
+		}
+	}
+	return valueFlags
+}
+
+// encounterFlags are shared by encounter and walk
+var encounterFlags = []commandFlag{
+	{name: "method", usage: "how to look: walk, surf, old-rod, ... (default walk)", takesValue: true},
+	{name: "version", usage: "game version whose encounters to use (default the first the area has)", takesValue: true},
 }
 
 // the below is an example structure of a map that maps strings to cliCommands
@@ -34,265 +92,859 @@ func getCommands() map[string]cliCommand {
 	return map[string]cliCommand{
 		"help": {
 			name:        "help",
+			usage:       "help",
 			description: "Displays a help message",
 			callback:    commandHelp,
 		},
 		"exit": {
 			name:        "exit",
+			usage:       "exit",
 			description: "Exit the Pokedex",
 			callback:    commandExit,
 		},
 		"map": {
 			name:        "map",
+			usage:       "map",
 			description: "Display names of 20 location areas",
-			callback:    commandMap,
+			flags: []commandFlag{
+				{name: "page", usage: "show this page instead of the next one", takesValue: true},
+			},
+			callback: commandMap,
 		},
 		"mapb": {
 			name:        "mapb",
+			usage:       "mapb",
 			description: "Display the previous 20 locations",
 			callback:    commandMapb,
 		},
 		"explore": {
-			name: "explore <area_name>",
-			description: "Display pokemon in given area",
-			callback: commandExplore,
+			name:        "explore",
+			usage:       "explore [area_name]",
+			description: "Display pokemon in the current area, or go to the given area",
+			maxArgs:     1,
+			callback:    commandExplore,
+		},
+		"travel": {
+			name:        "travel",
+			usage:       "travel <location_name>",
+			description: "Go to a location, see map for where you can go",
+			minArgs:     1,
+			maxArgs:     1,
+			flags: []commandFlag{
+				{name: "area", usage: "area of the location to go to (default the first)", takesValue: true},
+			},
+			notInBattle: true,
+			callback:    commandTravel,
+		},
+		"areas": {
+			name:        "areas",
+			usage:       "areas",
+			description: "List the areas of the current location",
+			callback:    commandAreas,
+		},
+		"region": {
+			name:        "region",
+			usage:       "region [region_name]",
+			description: "List the locations of the current region, or the given one",
+			maxArgs:     1,
+			callback:    commandRegion,
+		},
+		"encounter": {
+			name:        "encounter",
+			usage:       "encounter [area_name]",
+			description: "Look for a wild pokemon in the current area",
+			maxArgs:     1,
+			flags:       encounterFlags,
+			notInBattle: true,
+			callback:    commandEncounter,
+		},
+		"walk": {
+			name:        "walk",
+			usage:       "walk [area_name]",
+			description: "Walk through the tall grass of the current area, same as encounter",
+			maxArgs:     1,
+			flags:       encounterFlags,
+			notInBattle: true,
+			callback:    commandEncounter,
 		},
 		"catch": {
-			name: "catch <pokemon_name>",
+			name:        "catch",
+			usage:       "catch <pokemon_name>",
 			description: "Capture a pokemon",
+			minArgs:     1,
+			maxArgs:     1,
+			flags: []commandFlag{
+				{name: "ball", usage: "ball to throw: " + strings.Join(catch.BallNames(), ", "), takesValue: true},
+			},
 			callback: commandCatch,
 		},
 		"inspect": {
-			name: "inspect <pokemon_name>",
+			name:        "inspect",
+			usage:       "inspect <pokemon_name|id>",
 			description: "Inspect a pokemon in pokedex",
-			callback: commandInspect,
+			minArgs:     1,
+			maxArgs:     1,
+			callback:    commandInspect,
+		},
+		"matchup": {
+			name:        "matchup",
+			usage:       "matchup <pokemon|type> [vs <pokemon|type>]",
+			description: "Show the weaknesses, resistances and immunities of a pokemon or type (e.g. water/flying)",
+			minArgs:     1,
+			maxArgs:     3,
+			callback:    commandMatchup,
+		},
+		"evolutions": {
+			name:        "evolutions",
+			usage:       "evolutions <pokemon_name>",
+			description: "Show what a pokemon evolves from and into, and how",
+			minArgs:     1,
+			maxArgs:     1,
+			callback:    commandEvolutions,
+		},
+		"evolve": {
+			name:        "evolve",
+			usage:       "evolve <id>",
+			description: "Evolve a caught pokemon that is ready, by level or the item it holds (use an evolution stone on it instead)",
+			minArgs:     1,
+			maxArgs:     1,
+			notInBattle: true,
+			callback:    commandEvolve,
+		},
+		"bag": {
+			name:        "bag",
+			usage:       "bag [pocket]",
+			description: "List the items in your bag, or in one of its pockets: " + strings.Join(savefile.Pockets, ", "),
+			maxArgs:     1,
+			callback:    commandBag,
+		},
+		"item": {
+			name:        "item",
+			usage:       "item <name>",
+			description: "Describe an item in the language set, berries with how they grow",
+			minArgs:     1,
+			maxArgs:     1,
+			callback:    commandItem,
+		},
+		"use": {
+			name:        "use",
+			usage:       "use <item> [on <id>]",
+			description: "Use an item from your bag: throw a ball, or heal, restore PP, level up or evolve a caught pokemon",
+			minArgs:     1,
+			maxArgs:     3,
+			flags: []commandFlag{
+				{name: "move", usage: "the move to restore the PP of, for items that restore one", takesValue: true},
+			},
+			callback: commandUse,
+		},
+		"give": {
+			name:        "give",
+			usage:       "give <item> <id>",
+			description: "Have a caught pokemon hold an item from your bag, what it held goes back in the bag",
+			minArgs:     2,
+			maxArgs:     2,
+			notInBattle: true,
+			callback:    commandGive,
+		},
+		"shop": {
+			name:        "shop",
+			usage:       "shop",
+			description: "List what the Poké Mart sells and for how much",
+			callback:    commandShop,
+		},
+		"buy": {
+			name:        "buy",
+			usage:       "buy <item> [quantity]",
+			description: "Buy items at the Poké Mart",
+			minArgs:     1,
+			maxArgs:     2,
+			notInBattle: true,
+			callback:    commandBuy,
+		},
+		"sell": {
+			name:        "sell",
+			usage:       "sell <item> [quantity]",
+			description: "Sell items from your bag for half what they cost",
+			minArgs:     1,
+			maxArgs:     2,
+			notInBattle: true,
+			callback:    commandSell,
+		},
+		"ability": {
+			name:        "ability",
+			usage:       "ability <name>",
+			description: "Describe an ability in the language set and list the pokemon that can have it",
+			minArgs:     1,
+			maxArgs:     1,
+			callback:    commandAbility,
+		},
+		"moves": {
+			name:        "moves",
+			usage:       "moves <pokemon_name>",
+			description: "List the moves a pokemon can learn, in the game version set if there is one",
+			minArgs:     1,
+			maxArgs:     1,
+			flags: []commandFlag{
+				{name: "method", usage: "only moves learned this way: level-up, machine, egg, tutor, ...", takesValue: true},
+				{name: "version-group", usage: "version group whose learnset to list (e.g. red-blue)", takesValue: true},
+			},
+			callback: commandMoves,
+		},
+		"teach": {
+			name:        "teach",
+			usage:       "teach <id> <move>",
+			description: "Teach a caught pokemon a move it can learn",
+			minArgs:     2,
+			maxArgs:     2,
+			flags: []commandFlag{
+				{name: "replace", usage: "move to forget for it when the pokemon already knows 4", takesValue: true},
+			},
+			notInBattle: true,
+			callback:    commandTeach,
+		},
+		"forget": {
+			name:        "forget",
+			usage:       "forget <id> <move>",
+			description: "Make a caught pokemon forget a move",
+			minArgs:     2,
+			maxArgs:     2,
+			notInBattle: true,
+			callback:    commandForget,
 		},
 		"pokedex": {
-			name: "pokedex",
-			description: "inspect all pokemon in your pokedex",
+			name:        "pokedex",
+			usage:       "pokedex",
+			description: "List the pokemon you have seen and caught and how complete your pokedex is",
+			flags: []commandFlag{
+				{name: "region", usage: "only the pokemon in the pokedex of a region, e.g. kanto", takesValue: true},
+				{name: "generation", usage: "only the pokemon introduced in a generation, e.g. 3", takesValue: true},
+				{name: "missing", usage: "list the pokemon not caught yet instead"},
+			},
 			callback: commandPokedex,
 		},
+		"party": {
+			name:        "party",
+			usage:       "party",
+			description: "List the pokemon traveling with you",
+			callback:    commandParty,
+		},
+		"box": {
+			name:        "box",
+			usage:       "box [n]",
+			description: "List the pokemon in PC box n, box 1 by default",
+			maxArgs:     1,
+			callback:    commandBox,
+		},
+		"deposit": {
+			name:        "deposit",
+			usage:       "deposit <id>",
+			description: "Put a party pokemon in a PC box",
+			minArgs:     1,
+			maxArgs:     1,
+			flags: []commandFlag{
+				{name: "box", usage: "box to put it in (default the first with room)", takesValue: true},
+			},
+			notInBattle: true,
+			callback:    commandDeposit,
+		},
+		"withdraw": {
+			name:        "withdraw",
+			usage:       "withdraw <id>",
+			description: "Take a pokemon out of its PC box into your party",
+			minArgs:     1,
+			maxArgs:     1,
+			notInBattle: true,
+			callback:    commandWithdraw,
+		},
+		"swap": {
+			name:        "swap",
+			usage:       "swap <id> <id>",
+			description: "Swap the places of two pokemon in your party or boxes",
+			minArgs:     2,
+			maxArgs:     2,
+			notInBattle: true,
+			callback:    commandSwap,
+		},
+		"release": {
+			name:        "release",
+			usage:       "release <id>",
+			description: "Let a pokemon go for good",
+			minArgs:     1,
+			maxArgs:     1,
+			notInBattle: true,
+			callback:    commandRelease,
+		},
+		"fight": {
+			name:        "fight",
+			usage:       "fight <move>",
+			description: "Have your pokemon use a move on the wild pokemon",
+			minArgs:     1,
+			maxArgs:     1,
+			callback:    commandFight,
+		},
+		"switch": {
+			name:        "switch",
+			usage:       "switch <id>",
+			description: "Send out another party pokemon, the wild pokemon gets a free attack",
+			minArgs:     1,
+			maxArgs:     1,
+			callback:    commandSwitch,
+		},
+		"ball": {
+			name:        "ball",
+			usage:       "ball [ball_name]",
+			description: "Throw a ball at the wild pokemon you're battling: " + strings.Join(catch.BallNames(), ", "),
+			maxArgs:     1,
+			callback:    commandBall,
+		},
+		"flee": {
+			name:        "flee",
+			usage:       "flee",
+			description: "Try to get away from the wild pokemon",
+			callback:    commandFlee,
+		},
+		"battle": {
+			name:        "battle",
+			usage:       "battle",
+			description: "Show how the battle is going and the moves you can use",
+			callback:    commandBattle,
+		},
+		"heal": {
+			name:        "heal",
+			usage:       "heal",
+			description: "Restore all your pokemon to full health",
+			notInBattle: true,
+			callback:    commandHeal,
+		},
+		"save": {
+			name:        "save",
+			usage:       "save",
+			description: "Save your game",
+			callback:    commandSave,
+		},
+		"load": {
+			name:        "load",
+			usage:       "load <file>",
+			description: "Load a save file, later saves go to that file",
+			minArgs:     1,
+			maxArgs:     1,
+			notInBattle: true,
+			callback:    commandLoad,
+		},
+		"cache": {
+			name:        "cache",
+			usage:       "cache <stats|clear|prune>",
+			description: "Show or clean up cached PokeAPI responses",
+			minArgs:     1,
+			maxArgs:     1,
+			callback:    commandCache,
+		},
+		"set": {
+			name:        "set",
+			usage:       "set <setting> <value>",
+			description: "Change a setting: output and language (e.g. de) for this session, version (e.g. red-blue, or any) for this save",
+			minArgs:     2,
+			maxArgs:     2,
+			notInBattle: true,
+			callback:    commandSet,
+		},
+		"new-game": {
+			name:        "new-game",
+			usage:       "new-game",
+			description: "Throw away your progress and start over",
+			notInBattle: true,
+			callback:    commandNewGame,
+		},
 	}
 }
 
 // need some way to keep track of what is the current page, next page, and prev page
-// to do this, define struct to hold the next and prev page. Then pass refs (pointer) to
-// these structs when you call the respective commands
+// to do this, keep the page number the map commands last showed on the config struct.
+// Then pass refs (pointer) to it when you call the respective commands
 // Commands need to accept a pointer to a config struct as a param!
-// config struct contains the next and previous urls
 type config struct {
-	previous *string
-	next     string
-	cache pokecache.Cache
-	pokedex map[string]pokeapi.PokeAPIPokemonResponse
+	client *pokeapi.Client
+	// page of locations the map commands last displayed, -1 before map is called the first time
+	locationPage int
+	// set when the last page displayed was the final one, so map knows to stop
+	lastLocationPage bool
+	// the caches in front of the PokeAPI, kept here so the cache command can inspect them
+	// diskCache is nil when the disk cache is turned off
+	memoryCache *pokecache.Cache
+	diskCache   *pokecache.DiskCache
+	// output is the format results are shown in, one of the names in output.Names()
+	output string
+	// language is the PokeAPI language code (en, de, ja, ...) texts are shown in when the
+	// PokeAPI has them in it, English otherwise
+	language string
+	// save holds everything that is persisted between sessions, savePath is where it gets written
+	save     *savefile.Save
+	savePath string
+	// rng is where everything random (catches, encounters, ...) gets its numbers from, so a
+	// session can be replayed by starting it with the same seed
+	rng  *rand.Rand
+	seed int64
+	// now is the clock used for catch times, swapped out in tests like rng
+	now func() time.Time
+	// wild is the pokemon encounter found in the current area, nil when nothing is around
+	wild *wildPokemon
+	// battle is the fight with the wild pokemon, nil when not battling. typeChart holds the
+	// type relations, loadTypeChart fills it in the first time it's needed
+	battle    *battle.Battle
+	typeChart typechart.Chart
+	// versionGroup is the version group named by save.GameVersion once it has been fetched,
+	// use gameVersion to get it
+	versionGroup *pokeapi.PokeAPIVersionGroupResponse
 }
 
 // displays the names of 20 location areas in the Pokemon world
 // each subsequent call to map should display the next 20 locations
-func commandMap(config *config, args ...string) error {
-	nextURL := config.next
-	locationResponse := pokeapi.PokeAPILocationResponse{}
-	body := pokeapi.GetData(nextURL, &config.cache)
-	err := json.Unmarshal(body, &locationResponse)
-	if err != nil {			
-		fmt.Println("error with API request")
-		return err
+func commandMap(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	if pageFlag, ok := cmd.Flags["page"]; ok {
+		// pages count from 1 for the user and from 0 for the client
+		page, err := strconv.Atoi(pageFlag)
+		if err != nil || page < 1 {
+			return nil, fmt.Errorf("--page must be a number from 1 up, got '%s'", pageFlag)
+		}
+		return showLocationPage(ctx, config, cmd, page-1)
 	}
-	for _, location := range locationResponse.Results {
-		fmt.Println(location.Name)
+	if config.lastLocationPage {
+		return nil, errors.New("you're on the last page")
 	}
-	previous, next := locationResponse.Previous, locationResponse.Next
-	config.previous = previous
-	config.next = next
-	return nil
+	return showLocationPage(ctx, config, cmd, config.locationPage+1)
 }
 
 // similar to map command, displays the previous 20 locations
 // suggests, need a way to keep track of the page that you're currently on
-func commandMapb(config *config, args ...string) error {
-	if config.previous == nil {
-		return errors.New("you're on the first page")
+func commandMapb(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	if config.locationPage <= 0 {
+		return nil, errors.New("you're on the first page")
 	}
+	return showLocationPage(ctx, config, cmd, config.locationPage-1)
+}
 
-	// Derefence pointer to get the string value
-	previousURL := *config.previous
-	locationResponse := pokeapi.PokeAPILocationResponse{}
-	body := pokeapi.GetData(previousURL, &config.cache)
-	err := json.Unmarshal(body, &locationResponse)
-	if err != nil {			
-		fmt.Println("Invalid URL")
-		return err
+func showLocationPage(ctx context.Context, config *config, cmd cmdline.Command, page int) (any, error) {
+	locationResponse, err := config.client.ListLocations(ctx, page)
+	if err != nil {
+		return nil, apiError(err, "location page", "")
 	}
+	if len(locationResponse.Results) == 0 {
+		return nil, fmt.Errorf("there is no page %d", page+1)
+	}
+	config.locationPage = page
+	config.lastLocationPage = locationResponse.Next == ""
+
+	result := locationPageResult{Page: page + 1, Locations: []string{}}
 	for _, location := range locationResponse.Results {
-		fmt.Println(location.Name)
+		result.Locations = append(result.Locations, location.Name)
 	}
-	previous, next := locationResponse.Previous, locationResponse.Next
-	config.previous = previous
-	config.next = next
-	return nil
+	return result, nil
 }
 
-func commandExplore(config *config, args ...string) error {
-	if len(args) < 1 {
-		fmt.Println("You need to enter a location area")
-		return errors.New("not enough arguments")
-	}
-	if len(args) > 1 {
-		fmt.Println("Only one location may be accepted")
-		return errors.New("too many arguments")
-	}
-	areaName := args[0]
-	fmt.Printf("Exploring %v \n", areaName)
-	locationAreaResponse := pokeapi.PokeAPILocationAreaResponse{}
-	areaURL := "https://pokeapi.co/api/v2/location-area/" + areaName
-	body := pokeapi.GetData(areaURL, &config.cache)
-	err := json.Unmarshal(body, &locationAreaResponse)
+// commandExplore lists the pokemon of the current area, or moves to the area given first
+func commandExplore(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	areaName := config.save.Position.Area
+	if len(cmd.Args) > 0 {
+		areaName = cmd.Args[0]
+	}
+	if areaName == "" {
+		return nil, errNowhere
+	}
+	locationAreaResponse, err := config.client.GetLocationArea(ctx, areaName)
+	if err != nil {
+		return nil, apiError(err, "location area", areaName)
+	}
+	group, err := config.gameVersion(ctx)
 	if err != nil {
-		fmt.Println("Invalid LocationID")
-		return err
+		return nil, err
 	}
-	fmt.Println("Found Pokemon:")
+	if err := config.moveToArea(ctx, locationAreaResponse); err != nil {
+		return nil, err
+	}
+	result := exploreResult{Area: areaName, Pokemon: []string{}}
 	for _, encounter := range locationAreaResponse.PokemonEncounters {
-		fmt.Printf("- %v \n", encounter.Pokemon.Name)
+		// with a version set, only list pokemon that can be found in one of its games
+		found := group == nil
+		for _, details := range encounter.VersionDetails {
+			found = found || slices.Contains(versionNames(group), details.Version.Name)
+		}
+		if found {
+			result.Pokemon = append(result.Pokemon, encounter.Pokemon.Name)
+		}
+	}
+	if group != nil {
+		result.Version = group.Name
 	}
-	return nil
+	return result, nil
 }
 
-func commandCatch(config *config, args ...string) error {
-	if len(args) < 1 {
-		fmt.Println("You need to enter a pokemon to capture")
-		return errors.New("not enough arguments")
+func commandCatch(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	pokemonName := strings.ToLower(cmd.Args[0])
+	// you can only throw a ball at something that's actually in front of you
+	if config.wild == nil || config.wild.Pokemon != pokemonName {
+		return nil, fmt.Errorf("there's no wild %s here, use encounter to look for one", pokemonName)
 	}
-	if len(args) > 1 {
-		fmt.Println("Can only capture one pokemon at a time")
-		return errors.New("too many arguments")
+	ballName := catch.DefaultBall
+	if name, ok := cmd.Flags["ball"]; ok {
+		ballName = name
 	}
-	pokemonName := strings.ToLower(args[0])
-	pokemonUrl := "https://pokeapi.co/api/v2/pokemon/" + pokemonName
-	pokemonResponse := pokeapi.PokeAPIPokemonResponse{}
-	body := pokeapi.GetData(pokemonUrl, &config.cache)
-	err := json.Unmarshal(body, &pokemonResponse)
-	if err != nil {
-		fmt.Println("Invalid Pokemon Name")
-		return err
-	}
-	fmt.Printf("Throwing a pokeball at %s... \n", pokemonName)
-	catchChance := pokemonResponse.BaseExperience
-	catchRoll := rand.Intn(620)
-	if catchRoll > catchChance {
-		fmt.Printf("%s was caught! \n", pokemonName)
-		// add pokemon to pokedex
-		config.pokedex[pokemonName] = pokemonResponse
-	} else {
-		fmt.Printf("%s escaped! \n", pokemonName)
-	}
-	
-	return nil
+	return throwBall(ctx, config, ballName)
 }
 
-func commandInspect(config *config, args ...string) error {
-	if len(args) < 1 {
-		fmt.Println("You need to choose a pokemon to inspect")
-		return errors.New("not enough arguments")
+// throwBall throws a ball at the wild pokemon. In a battle a weakened pokemon is easier to
+// catch, and one that breaks free gets to attack
+func throwBall(ctx context.Context, config *config, ballName string) (any, error) {
+	// whichever command threw it, a fainted pokemon can't stand by while the wild one attacks
+	if config.battle != nil && config.battle.Player.Fainted() {
+		return nil, fmt.Errorf("%s fainted, send out another pokemon with switch <id> first", config.battle.Player.Name)
+	}
+	pokemonName := config.wild.Pokemon
+	pokemonResponse, err := config.client.GetPokemon(ctx, pokemonName)
+	if err != nil {
+		return nil, apiError(err, "Pokémon", pokemonName)
+	}
+	species, err := config.client.GetPokemonSpecies(ctx, pokemonResponse.Species.Name)
+	if err != nil {
+		return nil, apiError(err, "Pokémon species", pokemonResponse.Species.Name)
+	}
+	ball, err := catch.LookupBall(ballName)
+	if err != nil {
+		return nil, err
+	}
+	if config.save.ItemCount(ball.Name) == 0 {
+		return nil, fmt.Errorf("you don't have any %s left, check your bag", ball.Name)
+	}
+
+	if !config.save.HasRoom() {
+		return nil, fmt.Errorf("%w, release some pokemon to make room", savefile.ErrStorageFull)
+	}
+
+	// the moves are looked up before the ball is spent, so a failed lookup doesn't cost a
+	// ball or lose a pokemon that was already caught
+	snapshot := snapshotPokemon(pokemonResponse, config.wild, config.now())
+	if err := ensureMoves(ctx, config, &snapshot); err != nil {
+		return nil, err
+	}
+	if err := config.save.RemoveItem(ball.Name, 1); err != nil {
+		return nil, err
 	}
-	if len(args) > 1 {
-		fmt.Println("You can only inspect one pokemon at a time")
-		return errors.New("too many arguments")
+	result := catchResult{Pokemon: pokemonName, Ball: ball.Name, Legendary: species.IsLegendary || species.IsMythical}
+	// outside of a battle the wild pokemon is at full health
+	target := catch.Target{CaptureRate: species.CaptureRate}
+	if config.battle != nil {
+		target.MaxHP, target.HP = config.battle.Wild.MaxHP(), config.battle.Wild.HP
 	}
+	attempt := catch.Attempt(config.rng, target, ball)
+	result.Shakes, result.Caught = attempt.Shakes, attempt.Caught
+	if attempt.Caught {
+		// store the pokemon and save right away so a crash doesn't lose it
+		if config.battle != nil {
+			snapshot.Damage = config.battle.Wild.MaxHP() - config.battle.Wild.HP
+			syncPP(snapshot.Moves, config.battle.Wild)
+		}
+		caught, place, err := config.save.Catch(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		config.wild, config.battle = nil, nil
+		result.ID, result.Place = caught.ID, place.String()
+		if err := config.save.Write(config.savePath); err != nil {
+			return nil, fmt.Errorf("%s was caught but the game couldn't be saved: %w", pokemonName, err)
+		}
+		return result, nil
+	}
+
+	if config.battle != nil {
+		// the wild pokemon's turn comes after the throw, so it's kept with the result
+		result.Battle, err = wildTurnLines(config)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return result, config.save.Write(config.savePath)
+}
 
-	pokemonName := strings.ToLower(args[0])
-	pokemon, ok := config.pokedex[pokemonName]
+// commandInspect shows a caught pokemon, by ID or by species for the first one of it
+func commandInspect(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
+	pokemon, ok := findPokemon(config, cmd.Args[0])
 	if !ok {
-		fmt.Println("you have not caught that pokemon")
-		return errors.New("pokemon not registered")
+		return nil, errors.New("you have not caught that pokemon")
 	}
-	// print the name, height, weight, stats and type(s) of the Pokemon
-	fmt.Printf("Name: %s \n",pokemon.Name)
-	fmt.Printf("Height: %v \n", pokemon.Height)
-	fmt.Printf("Weight: %v \n", pokemon.Weight)
-	fmt.Println("Stats:")
-	for _, statList := range pokemon.Stats {
-		fmt.Printf(" -%s: %v \n", statList.Stat.Name, statList.BaseStat)
+	pokemonName := pokemon.Name
+	result := inspectResult{Pokemon: pokemon, StatValues: statValues(pokemon)}
+	// everything else is in the save, so when the PokeAPI can't be asked the pokemon is
+	// still shown, just without its species' abilities or the game's learnset
+	details, err := config.client.GetPokemon(ctx, pokemonName)
+	if err != nil {
+		result.Notice = fmt.Sprintf("%v, showing %s from the save", apiError(err, "Pokémon", pokemonName), pokemonName)
+		return result, nil
+	}
+	result.Abilities = speciesAbilities(details)
+	group, err := config.gameVersion(ctx)
+	if err != nil {
+		result.Notice = fmt.Sprintf("%v, showing %s without its learnset", err, pokemonName)
+		return result, nil
+	}
+	if group == nil {
+		return result, nil
 	}
 
-	fmt.Println("Types:")
-	for _, typeList := range pokemon.Types {
-		fmt.Printf(" -%s \n", typeList.Type.Name)
+	// moves and sprites differ from game to game, so ask the API about this one
+	result.Version = group.Name
+	result.Learnset = learnset(details, group.Name, "")
+	if sprites := details.Sprites.Versions[group.Generation.Name][group.Name]; sprites.FrontDefault != "" {
+		result.Sprite = sprites.FrontDefault
 	}
+	return result, nil
+}
 
-	return nil
+// statValues works out the actual stats of a caught pokemon from its base stats
+func statValues(pokemon savefile.Pokemon) []statValue {
+	values := make([]statValue, 0, len(pokemon.Stats))
+	for _, stat := range pokemon.Stats {
+		value := statValue{
+			Name: stat.Name,
+			Base: stat.BaseStat,
+			IV:   pokemon.IVs[stat.Name],
+			EV:   pokemon.EVs[stat.Name],
+		}
+		value.Value = stats.Compute(stat.Name, value.Base, value.IV, value.EV, pokemon.Level, pokemon.Nature)
+		values = append(values, value)
+	}
+	return values
 }
 
-func commandPokedex(config *config, args ...string) error {
-	if len(config.pokedex) < 1 {
-		fmt.Println("No pokemon in pokedex")
-		return errors.New("no pokemon captured")
+// apiError turns the errors coming back from pokeapi into a message the user can act on
+// what is the kind of thing we were looking up ("Pokémon", "location area") and name is
+// what the user typed in, if anything
+func apiError(err error, what, name string) error {
+	switch {
+	case errors.Is(err, pokeapi.ErrNotFound):
+		if name == "" {
+			return fmt.Errorf("no such %s", what)
+		}
+		return fmt.Errorf("no %s named '%s'", what, name)
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		return errors.New("the PokeAPI is rate limiting us, try again in a bit")
+	case errors.Is(err, context.Canceled):
+		return errors.New("cancelled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out looking up %s", what)
+	}
+
+	var httpErr *pokeapi.HTTPError
+	if errors.As(err, &httpErr) {
+		return fmt.Errorf("the PokeAPI returned status %d while looking up %s", httpErr.StatusCode, what)
 	}
-	fmt.Println("Your Pokedex:")
-	for _, pokemon := range(config.pokedex) {
-		fmt.Printf(" -%s \n", pokemon.Name)
+	var transportErr *pokeapi.TransportError
+	if errors.As(err, &transportErr) {
+		return fmt.Errorf("couldn't reach the PokeAPI: %v", transportErr.Err)
 	}
-	return nil
+	return err
 }
 
-func commandHelp(config *config, args ...string) error {
+func commandHelp(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
 	// do what criteria says when help command is called
 	fmt.Println("\nWelcome to the Pokedex!")
 	fmt.Println("Usage:")
 	fmt.Println()
-	for _, command := range getCommands() {
-		fmt.Printf("%s: %s\n", command.name, command.description)
+	// map order is random, sort so help reads the same every time
+	commands := getCommands()
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		command := commands[name]
+		fmt.Printf("%s: %s\n", command.usage, command.description)
+		for _, flag := range command.flags {
+			if flag.takesValue {
+				fmt.Printf("    --%s <value>: %s\n", flag.name, flag.usage)
+			} else {
+				fmt.Printf("    --%s: %s\n", flag.name, flag.usage)
+			}
+		}
+	}
+	fmt.Println()
+	fmt.Println("Every command also takes:")
+	for _, flag := range globalFlags {
+		fmt.Printf("    --%s: %s\n", flag.name, flag.usage)
 	}
+	fmt.Println()
+	fmt.Println("Every command can also be run straight from the shell, e.g. pokedexcli explore canalave-city-area --json")
 
 	fmt.Println()
-	return nil
+	return nil, nil
 }
 
-func commandExit(config *config, args ...string) error {
+func commandExit(ctx context.Context, config *config, cmd cmdline.Command) (any, error) {
 	fmt.Println("Exiting Pokedex")
-	os.Exit(0)
-	// if no errors, return nil
-	return nil
+	// let whoever is running commands stop cleanly instead of calling os.Exit from here
+	return nil, errExit
 }
 
-func parseInput(input string) (string, []string) {
-	parts := strings.Split(input, " ")
-	if len(parts) == 2 {
-		return parts[0], parts[1:]
+func main() {
+	timeout := flag.Duration("timeout", pokeapi.DefaultTimeout, "how long a single PokeAPI request may take before giving up")
+	savePath := flag.String("save", "", "save file to use (default $XDG_DATA_HOME/pokedexcli/save.json)")
+	useDiskCache := flag.Bool("disk-cache", true, "keep PokeAPI responses on disk between sessions")
+	cacheTTL := flag.Duration("cache-ttl", 24*time.Hour, "how long responses stay in the disk cache")
+	cacheMaxBytes := flag.Int("cache-max-bytes", 32<<20, "how many bytes of responses to keep in memory, 0 for no limit")
+	outputFormat := flag.String("output", output.DefaultFormat, "format results are shown in: "+strings.Join(output.Names(), ", "))
+	language := flag.String("lang", defaultLanguage, "language code for texts such as ability effects, e.g. de or ja")
+	seedFlag := flag.String("seed", "", "seed for the random number generator, to replay a session (default $POKEDEX_SEED, or random)")
+	debug := flag.Bool("debug", false, "print the seed and every PokeAPI request to stderr")
+	commands := flag.String("c", "", "run these ;-separated commands and exit instead of starting the REPL")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage:")
+		fmt.Fprintln(flag.CommandLine.Output(), "  pokedexcli [flags]                  start the REPL")
+		fmt.Fprintln(flag.CommandLine.Output(), "  pokedexcli [flags] -c \"cmd; cmd\"    run commands and exit")
+		fmt.Fprintln(flag.CommandLine.Output(), "  pokedexcli [flags] run <script>     run commands from a file and exit")
+		fmt.Fprintln(flag.CommandLine.Output(), "  pokedexcli [flags] <command> ...    run a single command, e.g. pokedexcli map --page 3")
+		fmt.Fprintln(flag.CommandLine.Output(), "Flags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if _, ok := output.Lookup(*outputFormat); !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown output format '%s'\n", *outputFormat)
+		os.Exit(2)
+	}
+	seed, err := chooseSeed(*seedFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: ", err)
+		os.Exit(2)
+	}
+	debugLog := log.New(io.Discard, "", 0)
+	if *debug {
+		debugLog = log.New(os.Stderr, "debug: ", 0)
+	}
+	debugLog.Printf("seed %d", seed)
+
+	if *savePath == "" {
+		path, err := savefile.DefaultPath()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: ", err)
+			os.Exit(1)
+		}
+		*savePath = path
+	}
+	// a missing save just means this is the first session, anything else is worth stopping for
+	// so we don't overwrite a save file we failed to read
+	save, err := savefile.Load(*savePath)
+	if errors.Is(err, os.ErrNotExist) {
+		save = savefile.New()
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: ", err)
+		fmt.Fprintln(os.Stderr, "fix or move the file, or pick another one with -save")
+		os.Exit(1)
+	}
+
+	// POKEAPI_BASE_URL lets you point the CLI at a self-hosted PokeAPI mirror
+	baseURL := os.Getenv("POKEAPI_BASE_URL")
+	if baseURL == "" {
+		baseURL = pokeapi.DefaultBaseURL
+	}
+	// memory first, then disk, then the network
+	memoryCache := pokecache.NewCache(100*time.Second, pokecache.WithMaxBytes(*cacheMaxBytes))
+	caches := []pokeapi.Cache{memoryCache}
+	var diskCache *pokecache.DiskCache
+	if *useDiskCache {
+		diskCache, err = openDiskCache(*cacheTTL)
+		if err != nil {
+			// we can still play without it, just slower
+			fmt.Fprintln(os.Stderr, "Warning: disk cache disabled:", err)
+		} else {
+			caches = append(caches, diskCache)
+		}
 	}
-	return parts[0], nil
-}
 
-func main() {
 	config := config{
-		next:     "https://pokeapi.co/api/v2/location/?limit=20",
-		previous: nil,
-		cache: *pokecache.NewCache(100 * time.Second),
-		pokedex: map[string]pokeapi.PokeAPIPokemonResponse{},
-	}
-	for {
-		// Create new scanner to read from stdin
-		scanner := bufio.NewScanner(os.Stdin)
-
-		fmt.Print("Pokedex > ")
-
-		// read input line by line
-		for scanner.Scan() {
-			input := scanner.Text()
-			// destructure command name and params from input
-			commandName, args := parseInput(input)
-			command, exists := getCommands()[commandName]
-			if exists {
-				err := command.callback(&config, args...)
-				if err != nil {
-					//handle error some type of way
-					fmt.Println("Error: ", err)
-				}
-				break
-			} else {
-				fmt.Println("Command does not exist")
-				break
-			}
+		client: pokeapi.NewClient(
+			pokeapi.WithBaseURL(baseURL),
+			pokeapi.WithCache(caches...),
+			pokeapi.WithTimeout(*timeout),
+			pokeapi.WithLogger(debugLog),
+		),
+		output:       *outputFormat,
+		language:     *language,
+		memoryCache:  memoryCache,
+		diskCache:    diskCache,
+		locationPage: -1,
+		save:         save,
+		savePath:     *savePath,
+		rng:          rand.New(rand.NewSource(seed)),
+		seed:         seed,
+		now:          time.Now,
+		typeChart:    typechart.Chart{},
+	}
+	code := run(&config, *commands, flag.Args())
+	// os.Exit skips deferred calls, so the reaper has to be stopped before it
+	memoryCache.Stop()
+	os.Exit(code)
+}
+
+// chooseSeed picks the RNG seed: the -seed flag wins, then $POKEDEX_SEED, and without
+// either every session gets a different one
+func chooseSeed(flagValue string) (int64, error) {
+	value, source := flagValue, "-seed"
+	if value == "" {
+		value, source = os.Getenv("POKEDEX_SEED"), "POKEDEX_SEED"
+	}
+	if value == "" {
+		return time.Now().UnixNano(), nil
+	}
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a whole number, got '%s'", source, value)
+	}
+	return seed, nil
+}
+
+// run picks the mode from the command line and returns the exit status:
+// -c commands, run <script>, or reading stdin (the REPL when stdin is a terminal)
+func run(config *config, commands string, args []string) int {
+	var r *runner
+	switch {
+	case commands != "":
+		r = newRunner(config, false)
+		r.runLine(commands)
+	case len(args) == 2 && args[0] == "run":
+		script, err := os.Open(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: ", err)
+			return 1
+		}
+		defer script.Close()
+		r = newRunner(config, false)
+		r.runLines(script, false)
+	case len(args) > 0:
+		// a single command straight from the shell, the shell already split the words for us
+		if _, exists := getCommands()[args[0]]; !exists {
+			fmt.Fprintf(os.Stderr, "Error: command '%s' does not exist\n\n", args[0])
+			flag.Usage()
+			return 2
 		}
+		r = newRunner(config, false)
+		r.runTokens(args)
+	default:
+		// only show the prompt to a person, not when input is piped in from a file or program
+		interactive := isTerminal(os.Stdin)
+		r = newRunner(config, interactive)
+		r.runLines(os.Stdin, interactive)
+		// a typo earlier in an interactive session isn't a reason to report failure
+		if interactive {
+			return 0
+		}
+	}
+	if r.failed {
+		return 1
 	}
+	return 0
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/staf3333/pokedexcli/internal/catch"
+	"github.com/staf3333/pokedexcli/internal/output"
+	"github.com/staf3333/pokedexcli/internal/pokeapi"
+	"github.com/staf3333/pokedexcli/internal/pokecache"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+	"github.com/staf3333/pokedexcli/internal/typechart"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenSeed is the seed TestGoldenSession replays with, changing it changes every catch
+const goldenSeed = 42
+
+// newFakePokeAPI serves testdata/pokeapi/<path>.json in place of the real PokeAPI
+func newFakePokeAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2"), "/")
+		data, err := os.ReadFile(filepath.Join("testdata", "pokeapi", path+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestConfig(t *testing.T, seed int64) *config {
+	t.Helper()
+	server := newFakePokeAPI(t)
+	memoryCache := pokecache.NewCache(time.Minute)
+	t.Cleanup(memoryCache.Stop)
+	// the golden scripts throw a lot of balls, more than the starter bag has
+	save := savefile.New()
+	for _, ball := range catch.BallNames() {
+		save.AddItem(ball, savefile.PocketBalls, 20)
+	}
+	return &config{
+		client: pokeapi.NewClient(
+			pokeapi.WithBaseURL(server.URL+"/api/v2"),
+			pokeapi.WithCache(memoryCache),
+		),
+		output:       output.DefaultFormat,
+		language:     defaultLanguage,
+		memoryCache:  memoryCache,
+		locationPage: -1,
+		save:         save,
+		savePath:     filepath.Join(t.TempDir(), "save.json"),
+		rng:          rand.New(rand.NewSource(seed)),
+		seed:         seed,
+		typeChart:    typechart.Chart{},
+		now: func() time.Time {
+			return time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+		},
+	}
+}
+
+// captureOutput runs f with stdout and stderr going to the same pipe and returns what
+// was written, in the order it was written
+func captureOutput(t *testing.T, f func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = writer, writer
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+	}()
+
+	captured := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		captured <- string(data)
+	}()
+	f()
+	writer.Close()
+	return <-captured
+}
+
+// TestGoldenSessions replays every testdata/<name>.txt with a fixed seed and compares the
+// whole transcript, exit status included, with testdata/<name>.golden. Run with -update
+// after an intended change
+func TestGoldenSessions(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, script := range scripts {
+		name := strings.TrimSuffix(filepath.Base(script), ".txt")
+		t.Run(name, func(t *testing.T) {
+			config := newTestConfig(t, goldenSeed)
+			transcript := captureOutput(t, func() {
+				code := run(config, "", []string{"run", script})
+				fmt.Printf("exit status %d\n", code)
+			})
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(transcript), 0o644); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if transcript != string(expected) {
+				t.Errorf("transcript doesn't match %s\nexpected:\n%s\ngot:\n%s", golden, expected, transcript)
+			}
+		})
+	}
+}
+
+func TestSameSeedSameCatches(t *testing.T) {
+	session := func() string {
+		config := newTestConfig(t, 7)
+		return captureOutput(t, func() {
+			run(config, "explore canalave-city-area; walk; catch pikachu; walk; catch pikachu; walk; catch pikachu", nil)
+		})
+	}
+	if first, second := session(), session(); first != second {
+		t.Errorf("expected the same seed to give the same session\nfirst:\n%s\nsecond:\n%s", first, second)
+	}
+}
+
+func TestChooseSeed(t *testing.T) {
+	t.Setenv("POKEDEX_SEED", "12")
+	if seed, err := chooseSeed("34"); err != nil || seed != 34 {
+		t.Errorf("expected the flag to win, got %d (%v)", seed, err)
+	}
+	if seed, err := chooseSeed(""); err != nil || seed != 12 {
+		t.Errorf("expected the environment seed, got %d (%v)", seed, err)
+	}
+	if _, err := chooseSeed("lucky"); err == nil {
+		t.Errorf("expected an error for a seed that isn't a number")
+	}
+}
+
+func TestPokedexNumbersOldEntries(t *testing.T) {
+	config := newTestConfig(t, goldenSeed)
+	// a pokedex saved before dex numbers were kept
+	config.save.Pokedex = []savefile.DexEntry{{Name: "tentacool", Caught: true}, {Name: "pikachu"}}
+	captureOutput(t, func() {
+		run(config, "pokedex", nil)
+	})
+	expected := []savefile.DexEntry{{Number: 25, Name: "pikachu"}, {Number: 72, Name: "tentacool", Caught: true}}
+	if !reflect.DeepEqual(config.save.Pokedex, expected) {
+		t.Errorf("expected the old entries numbered and in dex order, got %+v", config.save.Pokedex)
+	}
+}
+
+func TestInspectFromTheSave(t *testing.T) {
+	config := newTestConfig(t, goldenSeed)
+	// the fake PokeAPI doesn't know missingno, like the real one when it can't be reached
+	if _, _, err := config.save.Catch(savefile.Pokemon{Name: "missingno", Level: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var status int
+	transcript := captureOutput(t, func() {
+		status = run(config, "inspect 1", nil)
+	})
+	if status != 0 || !strings.Contains(transcript, "missingno") {
+		t.Errorf("expected missingno to be inspected from the save, got status %d:\n%s", status, transcript)
+	}
+}
+
+func TestPokedexWithoutPokeAPI(t *testing.T) {
+	config := newTestConfig(t, goldenSeed)
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	config.client = pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+	config.save.Register(savefile.DexEntry{Number: 25, Name: "pikachu", Caught: true})
+	// and one from before dex numbers were kept, which can't get its number now
+	config.save.Register(savefile.DexEntry{Name: "tentacool", Caught: true})
+	var status int
+	transcript := captureOutput(t, func() {
+		status = run(config, "pokedex", nil)
+	})
+	if status != 0 || !strings.Contains(transcript, "#25 pikachu (caught)\n -tentacool (caught)") {
+		t.Errorf("expected the pokedex to be listed without its completion, got status %d:\n%s", status, transcript)
+	}
+}
+
+func TestNoThrowWithAFaintedLead(t *testing.T) {
+	config := newTestConfig(t, goldenSeed)
+	captureOutput(t, func() {
+		run(config, "travel canalave-city; walk; catch pikachu --ball master; walk", nil)
+	})
+	if config.battle == nil {
+		t.Fatalf("expected a battle to start")
+	}
+	config.battle.Player.HP = 0
+	balls := config.save.ItemCount(catch.DefaultBall)
+	for _, script := range []string{"catch " + config.wild.Pokemon, "ball", "use " + catch.DefaultBall} {
+		var status int
+		transcript := captureOutput(t, func() {
+			status = run(config, script, nil)
+		})
+		if status == 0 || !strings.Contains(transcript, "fainted") || config.save.ItemCount(catch.DefaultBall) != balls {
+			t.Errorf("expected %q to be refused without spending a ball, got status %d:\n%s", script, status, transcript)
+		}
+	}
+}
+
+func TestGiveKeepsTheItemWhenTheHeldOneCantBeLookedUp(t *testing.T) {
+	config := newTestConfig(t, goldenSeed)
+	// the fake PokeAPI doesn't know light-ball, so it can't be put back in the bag
+	if _, _, err := config.save.Catch(savefile.Pokemon{Name: "pikachu", Level: 5, HeldItem: "light-ball"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := config.save.AddItem("oran-berry", savefile.PocketBerries, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var status int
+	transcript := captureOutput(t, func() {
+		status = run(config, "give oran-berry 1", nil)
+	})
+	if status == 0 || config.save.ItemCount("oran-berry") != 1 {
+		t.Errorf("expected give to fail and keep the oran-berry, got status %d and %d in the bag:\n%s",
+			status, config.save.ItemCount("oran-berry"), transcript)
+	}
+	if pokemon, _, _ := config.save.Get(1); pokemon.HeldItem != "light-ball" {
+		t.Errorf("expected pikachu to still hold the light-ball, got %q", pokemon.HeldItem)
+	}
+}
+
+func TestInspectWithoutPokeAPI(t *testing.T) {
+	config := newTestConfig(t, goldenSeed)
+	server := httptest.NewServer(http.NotFoundHandler())
+	// a closed server can't be reached at all
+	server.Close()
+	config.client = pokeapi.NewClient(pokeapi.WithBaseURL(server.URL))
+	config.save.GameVersion = "red-blue"
+	if _, _, err := config.save.Catch(savefile.Pokemon{Name: "pikachu", Level: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var status int
+	transcript := captureOutput(t, func() {
+		status = run(config, "inspect 1", nil)
+	})
+	if status != 0 || !strings.Contains(transcript, "couldn't reach the PokeAPI") || !strings.Contains(transcript, "Name: pikachu") {
+		t.Errorf("expected pikachu to be inspected from the save with a notice, got status %d:\n%s", status, transcript)
+	}
+}
+
+func TestLoadLeavesTheOldGameBehind(t *testing.T) {
+	other := filepath.Join(t.TempDir(), "other.json")
+	if err := savefile.New().Write(other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, script := range []string{"new-game", "load " + other} {
+		config := newTestConfig(t, goldenSeed)
+		captureOutput(t, func() {
+			run(config, "map; explore canalave-city-area; walk", nil)
+		})
+		if config.wild == nil {
+			t.Fatalf("expected a wild pokemon to be around")
+		}
+		transcript := captureOutput(t, func() {
+			run(config, script, nil)
+		})
+		if config.wild != nil || config.battle != nil || config.locationPage != -1 {
+			t.Errorf("expected %q to leave the wild pokemon and the map page behind, got %+v, page %d:\n%s",
+				script, config.wild, config.locationPage, transcript)
+		}
+	}
+}
+
+func TestSellWithNoRoomForTheMoney(t *testing.T) {
+	config := newTestConfig(t, goldenSeed)
+	config.save.Money = savefile.MaxMoney
+	balls := config.save.ItemCount("poke-ball")
+	var status int
+	transcript := captureOutput(t, func() {
+		status = run(config, "sell poke-ball", nil)
+	})
+	if status == 0 || config.save.ItemCount("poke-ball") != balls {
+		t.Errorf("expected the sale to be refused and the balls kept, got status %d and %d balls:\n%s",
+			status, config.save.ItemCount("poke-ball"), transcript)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/staf3333/pokedexcli/internal/cmdline"
+	"github.com/staf3333/pokedexcli/internal/output"
+)
+
+// errExit is returned by the exit command to stop whatever is reading commands
+var errExit = errors.New("exit")
+
+// checkCommand makes sure a command got the arguments and flags its registry entry asks for
+func checkCommand(command cliCommand, cmd cmdline.Command) error {
+	switch {
+	case len(cmd.Args) < command.minArgs:
+		return fmt.Errorf("not enough arguments, usage: %s", command.usage)
+	case len(cmd.Args) > command.maxArgs:
+		return fmt.Errorf("too many arguments, usage: %s", command.usage)
+	}
+	// go through the flags in order so the same mistake always gets the same error
+	names := make([]string, 0, len(cmd.Flags))
+	for name := range cmd.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := cmd.Flags[name]
+		known := false
+		for _, flag := range command.allFlags() {
+			if flag.name != name {
+				continue
+			}
+			known = true
+			if _, err := strconv.ParseBool(value); !flag.takesValue && err != nil {
+				return fmt.Errorf("--%s is a switch, it doesn't take the value '%s'", name, value)
+			}
+		}
+		if !known {
+			return fmt.Errorf("%s doesn't have a --%s flag", command.name, name)
+		}
+	}
+	// a bad format has to stop the command before it changes anything, not after
+	if format, ok := cmd.Flags["output"]; ok {
+		if _, ok := output.Lookup(format); !ok {
+			return fmt.Errorf("unknown output format '%s', pick one of %s", format, strings.Join(output.Names(), ", "))
+		}
+	}
+	return nil
+}
+
+// interruptHandler decides what Ctrl-C means: while a command is running it cancels that
+// command's context, so a slow request gets abandoned and we end up back at the prompt.
+// At the prompt itself it just reminds the user how to quit, and when running a script
+// between commands it stops the script
+type interruptHandler struct {
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	interactive bool
+}
+
+func (h *interruptHandler) watch(signals <-chan os.Signal) {
+	for range signals {
+		h.mu.Lock()
+		switch {
+		case h.cancel != nil:
+			h.cancel()
+		case h.interactive:
+			fmt.Print("\n(type exit to quit)\n" + prompt)
+		default:
+			// 128 + SIGINT, like a shell reports it
+			os.Exit(130)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// commandContext returns the context for the next command, call done once the command returns
+func (h *interruptHandler) commandContext() (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.mu.Lock()
+	h.cancel = cancel
+	h.mu.Unlock()
+	return ctx, func() {
+		h.mu.Lock()
+		h.cancel = nil
+		h.mu.Unlock()
+		cancel()
+	}
+}
+
+const prompt = "Pokedex > "
+
+// runner executes commands against a config and remembers whether any of them failed,
+// so batch mode can exit with a non-zero status
+type runner struct {
+	config     *config
+	interrupts *interruptHandler
+	failed     bool
+}
+
+func newRunner(config *config, interactive bool) *runner {
+	// take over SIGINT so Ctrl-C stops the running command instead of the whole program
+	interrupts := &interruptHandler{interactive: interactive}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go interrupts.watch(signals)
+	return &runner{config: config, interrupts: interrupts}
+}
+
+// runLines reads input line by line and runs every command in it until EOF or exit.
+// With showPrompt set it prints the prompt before each line, for a person typing at a terminal
+func (r *runner) runLines(input io.Reader, showPrompt bool) {
+	scanner := bufio.NewScanner(input)
+	for {
+		if showPrompt {
+			fmt.Print(prompt)
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		// lines starting with # are comments, handy in script files
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if r.runLine(line) == errExit {
+			return
+		}
+	}
+	if showPrompt {
+		// finish the prompt line when the user hits Ctrl-D
+		fmt.Println()
+	}
+	if err := scanner.Err(); err != nil {
+		r.fail(fmt.Errorf("reading input: %w", err))
+	}
+}
+
+// runLine runs each ;-separated command in line, it returns errExit if one of them was exit
+func (r *runner) runLine(line string) error {
+	// split the input into the words of each command
+	commands, err := cmdline.SplitCommands(line)
+	if err != nil {
+		r.fail(err)
+		return err
+	}
+	for _, tokens := range commands {
+		if err := r.runTokens(tokens); err == errExit {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTokens looks up the command named by the first token and runs it with the rest
+func (r *runner) runTokens(tokens []string) error {
+	command, exists := getCommands()[tokens[0]]
+	if !exists {
+		err := fmt.Errorf("command '%s' does not exist", tokens[0])
+		r.fail(err)
+		return err
+	}
+	// now that we know the command we know which of its flags take a value
+	cmd, err := cmdline.FromTokens(tokens, command.valueFlags())
+	if err == nil {
+		err = checkCommand(command, cmd)
+	}
+	if err == nil && command.notInBattle && r.config.battle != nil {
+		err = errInBattle
+	}
+	if err != nil {
+		r.fail(err)
+		return err
+	}
+	ctx, done := r.interrupts.commandContext()
+	result, err := command.callback(ctx, r.config, cmd)
+	done()
+	if err != nil && err != errExit {
+		r.fail(err)
+	}
+	if result != nil {
+		if err := r.render(cmd, result); err != nil {
+			r.fail(err)
+			return err
+		}
+	}
+	return err
+}
+
+// render shows a command's result in the format picked with --output/--json,
+// or the session's format otherwise
+func (r *runner) render(cmd cmdline.Command, result any) error {
+	format := r.config.output
+	if cmd.Bool("json") {
+		format = "json"
+	}
+	if name, ok := cmd.Flags["output"]; ok {
+		format = name
+	}
+	formatter, ok := output.Lookup(format)
+	if !ok {
+		return fmt.Errorf("unknown output format '%s', pick one of %s", format, strings.Join(output.Names(), ", "))
+	}
+	return formatter.Format(os.Stdout, result)
+}
+
+func (r *runner) fail(err error) {
+	r.failed = true
+	fmt.Fprintln(os.Stderr, "Error: ", err)
+}
@@ -0,0 +1,1321 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/staf3333/pokedexcli/internal/battle"
+	"github.com/staf3333/pokedexcli/internal/encounter"
+	"github.com/staf3333/pokedexcli/internal/evolution"
+	"github.com/staf3333/pokedexcli/internal/output"
+	"github.com/staf3333/pokedexcli/internal/savefile"
+	"github.com/staf3333/pokedexcli/internal/typechart"
+)
+
+// the results commands hand back to be rendered. Each one has json tags for the json and
+// yaml formats, a Table for table and csv, and WriteText for the default text format
+
+type locationPageResult struct {
+	Page      int      `json:"page"`
+	Locations []string `json:"locations"`
+}
+
+func (r locationPageResult) Table() output.Table {
+	table := output.Table{Columns: []string{"location"}}
+	for _, location := range r.Locations {
+		table.Rows = append(table.Rows, []string{location})
+	}
+	return table
+}
+
+func (r locationPageResult) WriteText(w io.Writer) error {
+	for _, location := range r.Locations {
+		fmt.Fprintln(w, location)
+	}
+	return nil
+}
+
+type exploreResult struct {
+	Area    string   `json:"area"`
+	Pokemon []string `json:"pokemon"`
+	// Version is the version group the list was filtered by, if any
+	Version string `json:"version,omitempty"`
+}
+
+func (r exploreResult) Table() output.Table {
+	table := output.Table{Columns: []string{"pokemon"}}
+	for _, name := range r.Pokemon {
+		table.Rows = append(table.Rows, []string{name})
+	}
+	return table
+}
+
+func (r exploreResult) WriteText(w io.Writer) error {
+	if r.Version != "" {
+		fmt.Fprintf(w, "Exploring %v (%v) \n", r.Area, r.Version)
+	} else {
+		fmt.Fprintf(w, "Exploring %v \n", r.Area)
+	}
+	fmt.Fprintln(w, "Found Pokemon:")
+	for _, name := range r.Pokemon {
+		fmt.Fprintf(w, "- %v \n", name)
+	}
+	return nil
+}
+
+type inspectResult struct {
+	savefile.Pokemon
+	// StatValues are the pokemon's actual stats, worked out from Stats, its IVs, EVs and nature
+	StatValues []statValue `json:"stat_values"`
+	// Abilities are every ability of the pokemon's species, Ability the one it has
+	Abilities []abilityHolder `json:"abilities,omitempty"`
+	// with a game version set, the moves the pokemon can learn in it
+	Version  string          `json:"version,omitempty"`
+	Learnset []learnableMove `json:"learnset,omitempty"`
+	// Notice says what was left out because the PokeAPI couldn't be asked
+	Notice string `json:"notice,omitempty"`
+}
+
+type statValue struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+	Base  int    `json:"base"`
+	IV    int    `json:"iv"`
+	EV    int    `json:"ev"`
+}
+
+func (s statValue) String() string {
+	return fmt.Sprintf("%d (base %d, iv %d, ev %d)", s.Value, s.Base, s.IV, s.EV)
+}
+
+type learnableMove struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	// Level is only set for moves learned by leveling up
+	Level int `json:"level,omitempty"`
+	// VersionGroup is only set when listing the moves of every game
+	VersionGroup string `json:"version_group,omitempty"`
+}
+
+func (m learnableMove) String() string {
+	how := m.Method
+	if m.Level > 0 {
+		how = fmt.Sprintf("%s %d", m.Method, m.Level)
+	}
+	if m.VersionGroup != "" {
+		how += " in " + m.VersionGroup
+	}
+	return fmt.Sprintf("%s (%s)", m.Name, how)
+}
+
+func movePP(move savefile.Move) string {
+	return fmt.Sprintf("%s (pp %d/%d)", move.Name, move.PP, move.MaxPP)
+}
+
+// Table lists the pokemon as field/value pairs, one row per stat
+func (r inspectResult) Table() output.Table {
+	table := output.Table{
+		Columns: []string{"field", "value"},
+		Rows: [][]string{
+			{"id", strconv.Itoa(r.ID)},
+			{"name", r.Name},
+			{"level", strconv.Itoa(r.Level)},
+			{"nature", r.Nature.Name},
+			{"gender", r.Gender},
+			{"shiny", strconv.FormatBool(r.Shiny)},
+			{"height", strconv.Itoa(r.Height)},
+			{"weight", strconv.Itoa(r.Weight)},
+		},
+	}
+	for _, stat := range r.StatValues {
+		table.Rows = append(table.Rows, []string{stat.Name, stat.String()})
+	}
+	table.Rows = append(table.Rows, []string{"types", strings.Join(r.Types, "/")})
+	if r.Ability != "" {
+		table.Rows = append(table.Rows, []string{"ability", r.Ability})
+	}
+	for _, ability := range r.Abilities {
+		table.Rows = append(table.Rows, []string{"abilities", ability.String()})
+	}
+	if r.HeldItem != "" {
+		table.Rows = append(table.Rows, []string{"held_item", r.HeldItem})
+	}
+	if r.Sprite != "" {
+		table.Rows = append(table.Rows, []string{"sprite", r.Sprite})
+	}
+	for _, move := range r.Moves {
+		table.Rows = append(table.Rows, []string{"move", movePP(move)})
+	}
+	for _, move := range r.Learnset {
+		table.Rows = append(table.Rows, []string{"learnset", move.String()})
+	}
+	return table
+}
+
+func (r inspectResult) WriteText(w io.Writer) error {
+	if r.Notice != "" {
+		fmt.Fprintln(w, r.Notice)
+	}
+	// print the name, height, weight, stats and type(s) of the Pokemon
+	name := r.Name
+	if r.Shiny {
+		name += " (shiny)"
+	}
+	fmt.Fprintf(w, "Name: %s #%d \n", name, r.ID)
+	fmt.Fprintf(w, "Level: %v \n", r.Level)
+	if r.Nature.Increased != r.Nature.Decreased {
+		fmt.Fprintf(w, "Nature: %s (+%s -%s) \n", r.Nature.Name, r.Nature.Increased, r.Nature.Decreased)
+	} else {
+		fmt.Fprintf(w, "Nature: %s \n", r.Nature.Name)
+	}
+	if r.Gender != "" {
+		fmt.Fprintf(w, "Gender: %s \n", r.Gender)
+	}
+	fmt.Fprintf(w, "Height: %v \n", r.Height)
+	fmt.Fprintf(w, "Weight: %v \n", r.Weight)
+	fmt.Fprintln(w, "Stats:")
+	for _, stat := range r.StatValues {
+		fmt.Fprintf(w, " -%s: %s \n", stat.Name, stat)
+	}
+
+	fmt.Fprintln(w, "Types:")
+	for _, typeName := range r.Types {
+		fmt.Fprintf(w, " -%s \n", typeName)
+	}
+	if r.Ability != "" {
+		fmt.Fprintf(w, "Ability: %s \n", r.Ability)
+	}
+	if len(r.Abilities) > 0 {
+		fmt.Fprintln(w, "Abilities:")
+		for _, ability := range r.Abilities {
+			fmt.Fprintf(w, " -%s \n", ability)
+		}
+	}
+	if r.HeldItem != "" {
+		fmt.Fprintf(w, "Held item: %s \n", r.HeldItem)
+	}
+	if r.Sprite != "" {
+		fmt.Fprintf(w, "Sprite: %s \n", r.Sprite)
+	}
+	if len(r.Moves) > 0 {
+		fmt.Fprintln(w, "Moves:")
+		for _, move := range r.Moves {
+			fmt.Fprintf(w, " -%s \n", movePP(move))
+		}
+	}
+	if r.Version != "" {
+		fmt.Fprintf(w, "Learnset in %s:\n", r.Version)
+		for _, move := range r.Learnset {
+			fmt.Fprintf(w, " -%s \n", move)
+		}
+	}
+	return nil
+}
+
+// movesResult is the learnset of a pokemon, Version and Method are set when it's filtered
+type movesResult struct {
+	Pokemon string          `json:"pokemon"`
+	Version string          `json:"version_group,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Moves   []learnableMove `json:"moves"`
+}
+
+func (r movesResult) Table() output.Table {
+	table := output.Table{Columns: []string{"move", "method", "level", "version_group"}}
+	for _, move := range r.Moves {
+		versionGroup := move.VersionGroup
+		if versionGroup == "" {
+			versionGroup = r.Version
+		}
+		table.Rows = append(table.Rows, []string{move.Name, move.Method, strconv.Itoa(move.Level), versionGroup})
+	}
+	return table
+}
+
+func (r movesResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Moves %s can learn", r.Pokemon)
+	if r.Method != "" {
+		fmt.Fprintf(w, " by %s", r.Method)
+	}
+	if r.Version != "" {
+		fmt.Fprintf(w, " in %s", r.Version)
+	}
+	fmt.Fprintln(w, ":")
+	for _, move := range r.Moves {
+		fmt.Fprintf(w, " -%s \n", move)
+	}
+	return nil
+}
+
+// learnResult is a move a caught pokemon learned with teach, the one it forgot for it if
+// any, or just the one it forgot with forget
+type learnResult struct {
+	ID      int    `json:"id"`
+	Pokemon string `json:"pokemon"`
+	Learned string `json:"learned,omitempty"`
+	Forgot  string `json:"forgot,omitempty"`
+}
+
+func (r learnResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"id", "pokemon", "learned", "forgot"},
+		Rows:    [][]string{{strconv.Itoa(r.ID), r.Pokemon, r.Learned, r.Forgot}},
+	}
+}
+
+func (r learnResult) WriteText(w io.Writer) error {
+	switch {
+	case r.Learned != "" && r.Forgot != "":
+		fmt.Fprintf(w, "1, 2 and... Poof! %s forgot %s and learned %s!\n", r.Pokemon, r.Forgot, r.Learned)
+	case r.Learned != "":
+		fmt.Fprintf(w, "%s learned %s!\n", r.Pokemon, r.Learned)
+	default:
+		fmt.Fprintf(w, "%s forgot %s\n", r.Pokemon, r.Forgot)
+	}
+	return nil
+}
+
+// abilityResult is an ability described in the language set, Language is the one the
+// effect ended up in, which is English when there's nothing in RequestedLanguage
+type abilityResult struct {
+	Name              string          `json:"name"`
+	DisplayName       string          `json:"display_name"`
+	RequestedLanguage string          `json:"requested_language"`
+	Language          string          `json:"language,omitempty"`
+	Effect            string          `json:"effect,omitempty"`
+	ShortEffect       string          `json:"short_effect,omitempty"`
+	Pokemon           []abilityHolder `json:"pokemon"`
+}
+
+// writeFallbackNotice says when a description isn't in the language that was asked for
+func writeFallbackNotice(w io.Writer, name, requested, shown string) {
+	if shown != "" && !sameLanguage(shown, requested) {
+		fmt.Fprintf(w, "%s has no %s description, showing it in %s\n", name, requested, shown)
+	}
+}
+
+// abilityHolder is a pokemon with an ability, or an ability of a pokemon
+type abilityHolder struct {
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+func (a abilityHolder) String() string {
+	if a.Hidden {
+		return a.Name + " (hidden)"
+	}
+	return a.Name
+}
+
+func (r abilityResult) Table() output.Table {
+	table := output.Table{Columns: []string{"pokemon", "hidden"}}
+	for _, holder := range r.Pokemon {
+		table.Rows = append(table.Rows, []string{holder.Name, strconv.FormatBool(holder.Hidden)})
+	}
+	return table
+}
+
+func (r abilityResult) WriteText(w io.Writer) error {
+	writeFallbackNotice(w, r.Name, r.RequestedLanguage, r.Language)
+	if r.DisplayName != r.Name {
+		fmt.Fprintf(w, "%s (%s)\n", r.DisplayName, r.Name)
+	} else {
+		fmt.Fprintln(w, r.Name)
+	}
+	if r.ShortEffect != "" {
+		fmt.Fprintf(w, "  %s\n", r.ShortEffect)
+	}
+	if r.Effect != "" && r.Effect != r.ShortEffect {
+		fmt.Fprintf(w, "Effect: %s\n", strings.Join(strings.Fields(r.Effect), " "))
+	}
+	fmt.Fprintln(w, "Pokemon with it:")
+	for _, holder := range r.Pokemon {
+		fmt.Fprintf(w, " -%s \n", holder)
+	}
+	return nil
+}
+
+// bagResult is the items in the bag, pocket by pocket, and the money the player has
+type bagResult struct {
+	Money int             `json:"money"`
+	Items []savefile.Item `json:"items"`
+}
+
+// formatMoney writes an amount of money like the games do, e.g. ₽3000
+func formatMoney(amount int) string {
+	return fmt.Sprintf("₽%d", amount)
+}
+
+func (r bagResult) Table() output.Table {
+	table := output.Table{Columns: []string{"pocket", "item", "quantity"}}
+	for _, item := range r.Items {
+		table.Rows = append(table.Rows, []string{item.Pocket, item.Name, strconv.Itoa(item.Quantity)})
+	}
+	return table
+}
+
+func (r bagResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Money: %s\n", formatMoney(r.Money))
+	if len(r.Items) == 0 {
+		fmt.Fprintln(w, "Your bag is empty")
+		return nil
+	}
+	pocket := ""
+	for _, item := range r.Items {
+		if item.Pocket != pocket {
+			pocket = item.Pocket
+			title := strings.ReplaceAll(pocket, "-", " ")
+			fmt.Fprintf(w, "%s:\n", strings.ToUpper(title[:1])+title[1:])
+		}
+		fmt.Fprintf(w, " -%s x%d \n", item.Name, item.Quantity)
+	}
+	return nil
+}
+
+// shopResult is what the Poké Mart sells, with the money the player has to spend
+type shopResult struct {
+	Money int        `json:"money"`
+	Items []shopItem `json:"items"`
+}
+
+type shopItem struct {
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+	InBag int    `json:"in_bag"`
+}
+
+func (r shopResult) Table() output.Table {
+	table := output.Table{Columns: []string{"item", "price", "in_bag"}}
+	for _, item := range r.Items {
+		table.Rows = append(table.Rows, []string{item.Name, strconv.Itoa(item.Price), strconv.Itoa(item.InBag)})
+	}
+	return table
+}
+
+func (r shopResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Welcome to the Poké Mart! You have %s\n", formatMoney(r.Money))
+	for _, item := range r.Items {
+		fmt.Fprintf(w, " -%s %s", item.Name, formatMoney(item.Price))
+		if item.InBag > 0 {
+			fmt.Fprintf(w, " (%d in your bag)", item.InBag)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// tradeResult is items bought or sold at the Poké Mart, Total is what they cost or earned
+// and Money what the player has after
+type tradeResult struct {
+	Action   string `json:"action"`
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+	Total    int    `json:"total"`
+	Money    int    `json:"money"`
+}
+
+func (r tradeResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"action", "item", "quantity", "total", "money"},
+		Rows:    [][]string{{r.Action, r.Item, strconv.Itoa(r.Quantity), strconv.Itoa(r.Total), strconv.Itoa(r.Money)}},
+	}
+}
+
+func (r tradeResult) WriteText(w io.Writer) error {
+	if r.Action == "sold" {
+		fmt.Fprintf(w, "Sold %d %s for %s, you have %s now\n", r.Quantity, r.Item, formatMoney(r.Total), formatMoney(r.Money))
+	} else {
+		fmt.Fprintf(w, "Bought %d %s for %s, you have %s left\n", r.Quantity, r.Item, formatMoney(r.Total), formatMoney(r.Money))
+	}
+	return nil
+}
+
+// itemResult is an item described in the language set, Language is the one the effect
+// ended up in, which is English when there's nothing in RequestedLanguage. Berry is only
+// set for berries
+type itemResult struct {
+	Name              string     `json:"name"`
+	DisplayName       string     `json:"display_name"`
+	Category          string     `json:"category"`
+	Pocket            string     `json:"pocket"`
+	Cost              int        `json:"cost"`
+	Holdable          bool       `json:"holdable"`
+	RequestedLanguage string     `json:"requested_language"`
+	Language          string     `json:"language,omitempty"`
+	Effect            string     `json:"effect,omitempty"`
+	ShortEffect       string     `json:"short_effect,omitempty"`
+	InBag             int        `json:"in_bag"`
+	Berry             *berryInfo `json:"berry,omitempty"`
+}
+
+// berryInfo is the plant a berry grows on. Flavors are keyed by flavor, only the ones the
+// berry has are kept
+type berryInfo struct {
+	Firmness         string         `json:"firmness"`
+	Size             int            `json:"size"`
+	GrowthTime       int            `json:"growth_time"`
+	MaxHarvest       int            `json:"max_harvest"`
+	NaturalGiftType  string         `json:"natural_gift_type"`
+	NaturalGiftPower int            `json:"natural_gift_power"`
+	Flavors          map[string]int `json:"flavors"`
+}
+
+// flavorList lists the flavors of a berry strongest first, e.g. "sour 10, dry 5"
+func (b berryInfo) flavorList() string {
+	names := make([]string, 0, len(b.Flavors))
+	for name := range b.Flavors {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if b.Flavors[names[i]] != b.Flavors[names[j]] {
+			return b.Flavors[names[i]] > b.Flavors[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s %d", name, b.Flavors[name])
+	}
+	return strings.Join(names, ", ")
+}
+
+// Table lists the item as field/value pairs
+func (r itemResult) Table() output.Table {
+	table := output.Table{
+		Columns: []string{"field", "value"},
+		Rows: [][]string{
+			{"name", r.Name},
+			{"category", r.Category},
+			{"pocket", r.Pocket},
+			{"cost", strconv.Itoa(r.Cost)},
+			{"holdable", strconv.FormatBool(r.Holdable)},
+			{"effect", r.ShortEffect},
+			{"in_bag", strconv.Itoa(r.InBag)},
+		},
+	}
+	if r.Berry != nil {
+		table.Rows = append(table.Rows,
+			[]string{"firmness", r.Berry.Firmness},
+			[]string{"flavors", r.Berry.flavorList()},
+			[]string{"natural_gift", fmt.Sprintf("%s %d", r.Berry.NaturalGiftType, r.Berry.NaturalGiftPower)},
+		)
+	}
+	return table
+}
+
+func (r itemResult) WriteText(w io.Writer) error {
+	writeFallbackNotice(w, r.Name, r.RequestedLanguage, r.Language)
+	if r.DisplayName != r.Name {
+		fmt.Fprintf(w, "%s (%s)\n", r.DisplayName, r.Name)
+	} else {
+		fmt.Fprintln(w, r.Name)
+	}
+	if r.ShortEffect != "" {
+		fmt.Fprintf(w, "  %s\n", r.ShortEffect)
+	}
+	fmt.Fprintf(w, "Category: %s, kept in the %s pocket\n", r.Category, r.Pocket)
+	fmt.Fprintf(w, "Cost: %d\n", r.Cost)
+	if r.Holdable {
+		fmt.Fprintln(w, "Can be held by a pokemon")
+	}
+	if r.Berry != nil {
+		fmt.Fprintf(w, "Berry: %s, %dmm, ripens in %d hours, up to %d per tree\n", r.Berry.Firmness, r.Berry.Size, r.Berry.GrowthTime*4, r.Berry.MaxHarvest)
+		fmt.Fprintf(w, "  Flavors: %s\n", r.Berry.flavorList())
+		fmt.Fprintf(w, "  Natural gift: %s %d\n", r.Berry.NaturalGiftType, r.Berry.NaturalGiftPower)
+	}
+	fmt.Fprintf(w, "In your bag: %d\n", r.InBag)
+	return nil
+}
+
+// useResult is an item used on a caught pokemon, Message says what it did. In a battle the
+// wild pokemon gets its turn after, which is kept in Battle
+type useResult struct {
+	Item    string   `json:"item"`
+	ID      int      `json:"id"`
+	Pokemon string   `json:"pokemon"`
+	Message string   `json:"message"`
+	Battle  []string `json:"battle,omitempty"`
+}
+
+func (r useResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"item", "id", "pokemon", "message"},
+		Rows:    [][]string{{r.Item, strconv.Itoa(r.ID), r.Pokemon, r.Message}},
+	}
+}
+
+func (r useResult) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, r.Message)
+	for _, line := range r.Battle {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// giveResult is an item a caught pokemon was given to hold, TookBack is what it held
+// before, which went back in the bag
+type giveResult struct {
+	Item     string `json:"item"`
+	ID       int    `json:"id"`
+	Pokemon  string `json:"pokemon"`
+	TookBack string `json:"took_back,omitempty"`
+}
+
+func (r giveResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"item", "id", "pokemon", "took_back"},
+		Rows:    [][]string{{r.Item, strconv.Itoa(r.ID), r.Pokemon, r.TookBack}},
+	}
+}
+
+func (r giveResult) WriteText(w io.Writer) error {
+	if r.TookBack != "" {
+		fmt.Fprintf(w, "Took the %s from %s and put it in the bag\n", r.TookBack, r.Pokemon)
+	}
+	fmt.Fprintf(w, "%s is now holding a %s\n", r.Pokemon, r.Item)
+	return nil
+}
+
+// pokedexResult is the pokedex, or the species of Pokedex when one was asked for. With
+// Missing the entries are the species not caught yet
+type pokedexResult struct {
+	Pokedex    string       `json:"pokedex,omitempty"`
+	Missing    bool         `json:"missing,omitempty"`
+	Entries    []dexRow     `json:"entries"`
+	Completion []completion `json:"completion"`
+}
+
+// dexRow is a species in the pokedex, Seen is only false when listing missing species
+type dexRow struct {
+	savefile.DexEntry
+	Seen bool `json:"seen"`
+}
+
+// completion is how many species of a pokedex or generation have been seen and caught,
+// Percent is the share caught
+type completion struct {
+	Pokedex string  `json:"pokedex"`
+	Total   int     `json:"total"`
+	Seen    int     `json:"seen"`
+	Caught  int     `json:"caught"`
+	Percent float64 `json:"percent"`
+}
+
+func (r pokedexResult) Table() output.Table {
+	table := output.Table{Columns: []string{"number", "name", "seen", "caught", "first_seen_in", "first_seen_at"}}
+	for _, entry := range r.Entries {
+		firstSeenAt := ""
+		if entry.FirstSeenAt != nil {
+			firstSeenAt = entry.FirstSeenAt.Format("2006-01-02 15:04")
+		}
+		number := ""
+		if entry.Number != 0 {
+			number = strconv.Itoa(entry.Number)
+		}
+		table.Rows = append(table.Rows, []string{
+			number,
+			entry.Name,
+			strconv.FormatBool(entry.Seen),
+			strconv.FormatBool(entry.Caught),
+			entry.FirstSeenIn,
+			firstSeenAt,
+		})
+	}
+	return table
+}
+
+func (r pokedexResult) WriteText(w io.Writer) error {
+	seen, caught := 0, 0
+	for _, entry := range r.Entries {
+		if entry.Seen {
+			seen++
+		}
+		if entry.Caught {
+			caught++
+		}
+	}
+	switch {
+	case r.Missing:
+		fmt.Fprintf(w, "Missing from the %s pokedex: %d\n", r.Pokedex, len(r.Entries))
+	case r.Pokedex != "":
+		fmt.Fprintf(w, "Your %s pokedex: %d seen, %d caught\n", r.Pokedex, seen, caught)
+	default:
+		fmt.Fprintf(w, "Your Pokedex: %d seen, %d caught\n", seen, caught)
+	}
+	for _, entry := range r.Entries {
+		// entries saved before dex numbers were kept may not have one yet
+		line := " -" + entry.Name
+		if entry.Number != 0 {
+			line = fmt.Sprintf(" -#%d %s", entry.Number, entry.Name)
+		}
+		if entry.Caught {
+			line += " (caught)"
+		} else if r.Missing && entry.Seen {
+			line += " (seen)"
+		}
+		switch {
+		case entry.FirstSeenIn != "" && entry.FirstSeenAt != nil:
+			line += fmt.Sprintf(", first seen in %s on %s", entry.FirstSeenIn, entry.FirstSeenAt.Format("2006-01-02"))
+		case entry.FirstSeenAt != nil:
+			line += fmt.Sprintf(", first seen on %s", entry.FirstSeenAt.Format("2006-01-02"))
+		}
+		fmt.Fprintln(w, line)
+	}
+	for _, c := range r.Completion {
+		fmt.Fprintf(w, "%s: caught %d of %d (%.1f%%), seen %d\n", c.Pokedex, c.Caught, c.Total, c.Percent, c.Seen)
+	}
+	return nil
+}
+
+// storageResult is the party, or a PC box when Box is set
+type storageResult struct {
+	Box     int                `json:"box,omitempty"`
+	Pokemon []savefile.Pokemon `json:"pokemon"`
+}
+
+func (r storageResult) Table() output.Table {
+	table := output.Table{Columns: []string{"id", "name", "types", "caught_at"}}
+	for _, pokemon := range r.Pokemon {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(pokemon.ID),
+			pokemon.Name,
+			strings.Join(pokemon.Types, "/"),
+			pokemon.CaughtAt.Format("2006-01-02 15:04"),
+		})
+	}
+	return table
+}
+
+func (r storageResult) WriteText(w io.Writer) error {
+	if r.Box == 0 {
+		fmt.Fprintf(w, "Your party (%d/%d):\n", len(r.Pokemon), savefile.PartySize)
+	} else {
+		fmt.Fprintf(w, "Box %d (%d/%d):\n", r.Box, len(r.Pokemon), savefile.BoxSize)
+	}
+	for _, pokemon := range r.Pokemon {
+		fmt.Fprintf(w, " #%d %s (%s)\n", pokemon.ID, pokemon.Name, strings.Join(pokemon.Types, "/"))
+	}
+	return nil
+}
+
+// movedResult is where a pokemon went after being deposited or withdrawn
+type movedResult struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Place string `json:"place"`
+}
+
+func (r movedResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"id", "name", "place"},
+		Rows:    [][]string{{strconv.Itoa(r.ID), r.Name, r.Place}},
+	}
+}
+
+func (r movedResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s (#%d) is now in %s\n", r.Name, r.ID, r.Place)
+	return nil
+}
+
+type swapResult struct {
+	Swapped [2]int `json:"swapped"`
+}
+
+func (r swapResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"id", "with"},
+		Rows:    [][]string{{strconv.Itoa(r.Swapped[0]), strconv.Itoa(r.Swapped[1])}},
+	}
+}
+
+func (r swapResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Swapped #%d and #%d\n", r.Swapped[0], r.Swapped[1])
+	return nil
+}
+
+type releaseResult struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r releaseResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"id", "name"},
+		Rows:    [][]string{{strconv.Itoa(r.ID), r.Name}},
+	}
+}
+
+func (r releaseResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s (#%d) was released. Bye, %s!\n", r.Name, r.ID, r.Name)
+	return nil
+}
+
+type encounterResult struct {
+	Area string `json:"area"`
+	encounter.Encounter
+	Gender string `json:"gender,omitempty"`
+	Shiny  bool   `json:"shiny"`
+	// Partner is the party pokemon sent out to battle the wild one, if any could
+	Partner string `json:"partner,omitempty"`
+}
+
+func (r encounterResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"area", "pokemon", "level", "gender", "shiny", "version", "method"},
+		Rows: [][]string{{
+			r.Area, r.Pokemon, strconv.Itoa(r.Level), r.Gender, strconv.FormatBool(r.Shiny), r.Version, r.Method,
+		}},
+	}
+}
+
+func (r encounterResult) WriteText(w io.Writer) error {
+	name := r.Pokemon
+	if r.Shiny {
+		name = "shiny " + name
+	}
+	if r.Gender != "" {
+		fmt.Fprintf(w, "A wild %s (level %d, %s) appeared! \n", name, r.Level, r.Gender)
+	} else {
+		fmt.Fprintf(w, "A wild %s (level %d) appeared! \n", name, r.Level)
+	}
+	if r.Partner != "" {
+		fmt.Fprintf(w, "Go! %s!\n", r.Partner)
+	}
+	return nil
+}
+
+// catchResult is a ball thrown at the wild pokemon. A caught pokemon gets an ID and a
+// place, one that broke free in a battle gets its turn, which is kept in Battle
+type catchResult struct {
+	Pokemon   string   `json:"pokemon"`
+	Ball      string   `json:"ball"`
+	Legendary bool     `json:"legendary,omitempty"`
+	Shakes    int      `json:"shakes"`
+	Caught    bool     `json:"caught"`
+	ID        int      `json:"id,omitempty"`
+	Place     string   `json:"place,omitempty"`
+	Battle    []string `json:"battle,omitempty"`
+}
+
+func (r catchResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"pokemon", "ball", "shakes", "caught", "id", "place"},
+		Rows: [][]string{{
+			r.Pokemon, r.Ball, strconv.Itoa(r.Shakes), strconv.FormatBool(r.Caught), strconv.Itoa(r.ID), r.Place,
+		}},
+	}
+}
+
+func (r catchResult) WriteText(w io.Writer) error {
+	if r.Legendary {
+		fmt.Fprintf(w, "%s is a legendary pokemon, this won't be easy...\n", r.Pokemon)
+	}
+	fmt.Fprintf(w, "Throwing a %s at %s... \n", r.Ball, r.Pokemon)
+	for i := 0; i < r.Shakes; i++ {
+		fmt.Fprintln(w, "  ...shake...")
+	}
+	if !r.Caught {
+		fmt.Fprintf(w, "%s escaped! \n", r.Pokemon)
+		for _, line := range r.Battle {
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	}
+	fmt.Fprintf(w, "%s was caught! \n", r.Pokemon)
+	if r.Place != (savefile.Place{}).String() {
+		fmt.Fprintf(w, "Your party is full, %s (#%d) was sent to %s\n", r.Pokemon, r.ID, r.Place)
+	}
+	return nil
+}
+
+// battleResult is how a battle stands: both pokemon, their HP and the player's moves
+type battleResult struct {
+	Player battler `json:"player"`
+	Wild   battler `json:"wild"`
+}
+
+type battler struct {
+	Name  string        `json:"name"`
+	Level int           `json:"level"`
+	HP    int           `json:"hp"`
+	MaxHP int           `json:"max_hp"`
+	Moves []battlerMove `json:"moves,omitempty"`
+}
+
+type battlerMove struct {
+	Name string `json:"name"`
+	PP   int    `json:"pp"`
+}
+
+func newBattleResult(b *battle.Battle) battleResult {
+	result := battleResult{
+		Player: battler{Name: b.Player.Name, Level: b.Player.Level, HP: b.Player.HP, MaxHP: b.Player.MaxHP()},
+		Wild:   battler{Name: b.Wild.Name, Level: b.Wild.Level, HP: b.Wild.HP, MaxHP: b.Wild.MaxHP()},
+	}
+	for _, move := range b.Player.Moves {
+		result.Player.Moves = append(result.Player.Moves, battlerMove{Name: move.Name, PP: move.PP})
+	}
+	return result
+}
+
+func (r battleResult) Table() output.Table {
+	table := output.Table{Columns: []string{"side", "name", "level", "hp", "max_hp"}}
+	for _, side := range []struct {
+		name string
+		battler
+	}{{"player", r.Player}, {"wild", r.Wild}} {
+		table.Rows = append(table.Rows, []string{
+			side.name, side.Name, strconv.Itoa(side.Level), strconv.Itoa(side.HP), strconv.Itoa(side.MaxHP),
+		})
+	}
+	return table
+}
+
+func (r battleResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Wild %s (level %d): %d/%d HP\n", r.Wild.Name, r.Wild.Level, r.Wild.HP, r.Wild.MaxHP)
+	fmt.Fprintf(w, "Your %s (level %d): %d/%d HP\n", r.Player.Name, r.Player.Level, r.Player.HP, r.Player.MaxHP)
+	fmt.Fprintln(w, "Moves:")
+	for _, move := range r.Player.Moves {
+		fmt.Fprintf(w, " - %s (pp %d)\n", move.Name, move.PP)
+	}
+	return nil
+}
+
+// turnResult is what happened in a battle turn, told line by line
+type turnResult struct {
+	Turn []string `json:"turn"`
+}
+
+// newTurnResult splits what was told about a turn into its lines
+func newTurnResult(turn *strings.Builder) turnResult {
+	return turnResult{Turn: strings.Split(strings.TrimSuffix(turn.String(), "\n"), "\n")}
+}
+
+func (r turnResult) Table() output.Table {
+	table := output.Table{Columns: []string{"turn"}}
+	for _, line := range r.Turn {
+		table.Rows = append(table.Rows, []string{line})
+	}
+	return table
+}
+
+func (r turnResult) WriteText(w io.Writer) error {
+	for _, line := range r.Turn {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// healResult is a visit to the Pokémon Center, Pokemon is how many were healed
+type healResult struct {
+	Pokemon int `json:"pokemon"`
+}
+
+func (r healResult) Table() output.Table {
+	return output.Table{Columns: []string{"pokemon"}, Rows: [][]string{{strconv.Itoa(r.Pokemon)}}}
+}
+
+func (r healResult) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, "Your pokemon are fully healed. We hope to see you again!")
+	return nil
+}
+
+type travelResult struct {
+	savefile.Position
+	Areas []string `json:"areas"`
+}
+
+func (r travelResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"region", "location", "area"},
+		Rows:    [][]string{{r.Region, r.Location, r.Area}},
+	}
+}
+
+func (r travelResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "You traveled to %s", r.Location)
+	if r.Region != "" {
+		fmt.Fprintf(w, " in %s", r.Region)
+	}
+	fmt.Fprintln(w)
+	if r.Area == "" {
+		fmt.Fprintln(w, "There's nowhere to explore here")
+		return nil
+	}
+	fmt.Fprintf(w, "You are in %s", r.Area)
+	if len(r.Areas) > 1 {
+		fmt.Fprint(w, ", use areas to see the others")
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+type areasResult struct {
+	savefile.Position
+	Areas []string `json:"areas"`
+}
+
+func (r areasResult) Table() output.Table {
+	return hereTable("area", r.Areas, r.Area)
+}
+
+func (r areasResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Areas of %s:\n", r.Location)
+	writeHereList(w, r.Areas, r.Area)
+	return nil
+}
+
+type regionResult struct {
+	Region    string   `json:"region"`
+	Locations []string `json:"locations"`
+	// Current is the location the player is in, if it's in this region
+	Current string `json:"current,omitempty"`
+}
+
+func (r regionResult) Table() output.Table {
+	return hereTable("location", r.Locations, r.Current)
+}
+
+func (r regionResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Locations of %s:\n", r.Region)
+	writeHereList(w, r.Locations, r.Current)
+	return nil
+}
+
+// hereTable and writeHereList show a list of places with the one the player is at marked
+func hereTable(column string, names []string, here string) output.Table {
+	table := output.Table{Columns: []string{column, "here"}}
+	for _, name := range names {
+		table.Rows = append(table.Rows, []string{name, strconv.FormatBool(name == here)})
+	}
+	return table
+}
+
+func writeHereList(w io.Writer, names []string, here string) {
+	for _, name := range names {
+		if name == here {
+			fmt.Fprintf(w, "- %s (you are here)\n", name)
+		} else {
+			fmt.Fprintf(w, "- %s \n", name)
+		}
+	}
+}
+
+// typedSide is a pokemon, or a made up one when only its types were given
+type typedSide struct {
+	Name  string   `json:"name"`
+	Types []string `json:"types"`
+}
+
+func (s typedSide) String() string {
+	types := strings.Join(s.Types, "/")
+	if s.Name == types {
+		return types
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, types)
+}
+
+// typeMultiplier is how effective moves of Type are against something
+type typeMultiplier struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+func (m typeMultiplier) String() string {
+	return fmt.Sprintf("%s (x%s)", m.Type, strconv.FormatFloat(m.Multiplier, 'g', -1, 64))
+}
+
+// matchupResult sorts the attacking types by how well they do against a pokemon or type,
+// the ones that do neutral damage are left out
+type matchupResult struct {
+	typedSide
+	Weaknesses  []typeMultiplier `json:"weaknesses"`
+	Resistances []typeMultiplier `json:"resistances"`
+	Immunities  []string         `json:"immunities"`
+}
+
+func newMatchupResult(chart typechart.Chart, side typedSide) matchupResult {
+	result := matchupResult{typedSide: side, Weaknesses: []typeMultiplier{}, Resistances: []typeMultiplier{}, Immunities: []string{}}
+	defense := chart.Defense(side.Types...)
+	for _, attacking := range typechart.Names {
+		switch multiplier := defense[attacking]; {
+		case multiplier == 0:
+			result.Immunities = append(result.Immunities, attacking)
+		case multiplier > 1:
+			result.Weaknesses = append(result.Weaknesses, typeMultiplier{attacking, multiplier})
+		case multiplier < 1:
+			result.Resistances = append(result.Resistances, typeMultiplier{attacking, multiplier})
+		}
+	}
+	// a double weakness or resistance matters most, so it comes first
+	sort.SliceStable(result.Weaknesses, func(i, j int) bool {
+		return result.Weaknesses[i].Multiplier > result.Weaknesses[j].Multiplier
+	})
+	sort.SliceStable(result.Resistances, func(i, j int) bool {
+		return result.Resistances[i].Multiplier < result.Resistances[j].Multiplier
+	})
+	return result
+}
+
+func (r matchupResult) Table() output.Table {
+	table := output.Table{Columns: []string{"type", "multiplier"}}
+	for _, multiplier := range append(slices.Clone(r.Weaknesses), r.Resistances...) {
+		table.Rows = append(table.Rows, []string{multiplier.Type, strconv.FormatFloat(multiplier.Multiplier, 'g', -1, 64)})
+	}
+	for _, immunity := range r.Immunities {
+		table.Rows = append(table.Rows, []string{immunity, "0"})
+	}
+	return table
+}
+
+func (r matchupResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s\n", r.typedSide)
+	writeSection := func(title string, entries []string) {
+		fmt.Fprintf(w, "%s:\n", title)
+		if len(entries) == 0 {
+			fmt.Fprintln(w, " - nothing")
+		}
+		for _, entry := range entries {
+			fmt.Fprintf(w, " - %s\n", entry)
+		}
+	}
+	writeSection("Weak to", stringsOf(r.Weaknesses))
+	writeSection("Resists", stringsOf(r.Resistances))
+	writeSection("Immune to", r.Immunities)
+	return nil
+}
+
+// versusResult is how the moves of two pokemon or types do against each other
+type versusResult struct {
+	Attacker typedSide `json:"attacker"`
+	Defender typedSide `json:"defender"`
+	// Offense are the attacker's types against the defender, Defense the other way around
+	Offense []typeMultiplier `json:"offense"`
+	Defense []typeMultiplier `json:"defense"`
+}
+
+func (r versusResult) Table() output.Table {
+	table := output.Table{Columns: []string{"attacker", "type", "defender", "multiplier"}}
+	for _, side := range []struct {
+		attacker, defender typedSide
+		multipliers        []typeMultiplier
+	}{{r.Attacker, r.Defender, r.Offense}, {r.Defender, r.Attacker, r.Defense}} {
+		for _, multiplier := range side.multipliers {
+			table.Rows = append(table.Rows, []string{
+				side.attacker.Name, multiplier.Type, side.defender.Name, strconv.FormatFloat(multiplier.Multiplier, 'g', -1, 64),
+			})
+		}
+	}
+	return table
+}
+
+func (r versusResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s vs %s\n", r.Attacker, r.Defender)
+	writeMoves := func(attacker, defender typedSide, multipliers []typeMultiplier) {
+		fmt.Fprintf(w, "%s moves against %s:\n", attacker.Name, defender.Name)
+		for _, multiplier := range multipliers {
+			fmt.Fprintf(w, " - %s, %s\n", multiplier, effectiveness(multiplier.Multiplier))
+		}
+	}
+	writeMoves(r.Attacker, r.Defender, r.Offense)
+	writeMoves(r.Defender, r.Attacker, r.Defense)
+	return nil
+}
+
+func effectiveness(multiplier float64) string {
+	switch {
+	case multiplier == 0:
+		return "no effect"
+	case multiplier > 1:
+		return "super effective"
+	case multiplier < 1:
+		return "not very effective"
+	}
+	return "neutral"
+}
+
+func stringsOf[T fmt.Stringer](values []T) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, value.String())
+	}
+	return strs
+}
+
+// evolutionsResult is the evolution family Species belongs to
+type evolutionsResult struct {
+	Species string          `json:"species"`
+	Chain   evolution.Stage `json:"chain"`
+}
+
+func (r evolutionsResult) Table() output.Table {
+	table := output.Table{Columns: []string{"species", "evolves_from", "how"}}
+	var addStage func(stage evolution.Stage, from string)
+	addStage = func(stage evolution.Stage, from string) {
+		table.Rows = append(table.Rows, []string{stage.Species, from, strings.Join(stage.How, " or ")})
+		for _, next := range stage.EvolvesTo {
+			addStage(next, stage.Species)
+		}
+	}
+	addStage(r.Chain, "")
+	return table
+}
+
+func (r evolutionsResult) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, r.Chain.Species)
+	var writeStages func(stages []evolution.Stage, indent string)
+	writeStages = func(stages []evolution.Stage, indent string) {
+		for i, stage := range stages {
+			branch, nextIndent := "├─ ", indent+"│  "
+			if i == len(stages)-1 {
+				branch, nextIndent = "└─ ", indent+"   "
+			}
+			fmt.Fprintf(w, "%s%s%s (%s)\n", indent, branch, stage.Species, strings.Join(stage.How, ", or "))
+			writeStages(stage.EvolvesTo, nextIndent)
+		}
+	}
+	writeStages(r.Chain.EvolvesTo, "")
+	if len(r.Chain.EvolvesTo) == 0 {
+		fmt.Fprintf(w, "%s doesn't evolve\n", r.Species)
+	}
+	return nil
+}
+
+type evolveResult struct {
+	ID   int    `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	How  string `json:"how"`
+}
+
+func (r evolveResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"id", "from", "to", "how"},
+		Rows:    [][]string{{strconv.Itoa(r.ID), r.From, r.To, r.How}},
+	}
+}
+
+func (r evolveResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "What? %s (#%d) is evolving!\n", r.From, r.ID)
+	fmt.Fprintf(w, "Congratulations! Your %s evolved into %s!\n", r.From, r.To)
+	return nil
+}
+
+// gameResult is the save file after save, load or new-game. Pokemon is how many are in
+// the party and boxes
+type gameResult struct {
+	Action  string `json:"action"`
+	Path    string `json:"path"`
+	Pokemon int    `json:"pokemon"`
+}
+
+func (r gameResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"action", "path", "pokemon"},
+		Rows:    [][]string{{r.Action, r.Path, strconv.Itoa(r.Pokemon)}},
+	}
+}
+
+func (r gameResult) WriteText(w io.Writer) error {
+	switch r.Action {
+	case "loaded":
+		fmt.Fprintf(w, "Loaded %s, %d pokemon in your party and boxes\n", r.Path, r.Pokemon)
+	case "new":
+		fmt.Fprintln(w, "Started a new game, your pokedex is empty")
+	default:
+		fmt.Fprintf(w, "Game saved to %s\n", r.Path)
+	}
+	return nil
+}
+
+type settingResult struct {
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+}
+
+func (r settingResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"setting", "value"},
+		Rows:    [][]string{{r.Setting, r.Value}},
+	}
+}
+
+func (r settingResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s set to %s\n", r.Setting, r.Value)
+	return nil
+}
+
+// cacheStatsResult is what the caches hold, Disk is nil when the disk cache is turned off
+type cacheStatsResult struct {
+	Memory cacheStats  `json:"memory"`
+	Disk   *cacheStats `json:"disk"`
+}
+
+// cacheStats is a cache's size, the counters only apply to the memory cache and Expired
+// only to the disk cache
+type cacheStats struct {
+	Entries     int   `json:"entries"`
+	Bytes       int64 `json:"bytes"`
+	Hits        int   `json:"hits,omitempty"`
+	Misses      int   `json:"misses,omitempty"`
+	Evictions   int   `json:"evictions,omitempty"`
+	Expirations int   `json:"expirations,omitempty"`
+	Expired     int   `json:"expired,omitempty"`
+}
+
+func (r cacheStatsResult) Table() output.Table {
+	table := output.Table{Columns: []string{"cache", "entries", "bytes", "hits", "misses", "evictions", "expired"}}
+	table.Rows = append(table.Rows, []string{
+		"memory", strconv.Itoa(r.Memory.Entries), strconv.FormatInt(r.Memory.Bytes, 10),
+		strconv.Itoa(r.Memory.Hits), strconv.Itoa(r.Memory.Misses), strconv.Itoa(r.Memory.Evictions), strconv.Itoa(r.Memory.Expirations),
+	})
+	if r.Disk != nil {
+		table.Rows = append(table.Rows, []string{
+			"disk", strconv.Itoa(r.Disk.Entries), strconv.FormatInt(r.Disk.Bytes, 10), "", "", "", strconv.Itoa(r.Disk.Expired),
+		})
+	}
+	return table
+}
+
+func (r cacheStatsResult) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Memory cache: %d entries, %d bytes\n", r.Memory.Entries, r.Memory.Bytes)
+	fmt.Fprintf(w, "  %d hits, %d misses, %d evicted, %d expired\n", r.Memory.Hits, r.Memory.Misses, r.Memory.Evictions, r.Memory.Expirations)
+	if r.Disk == nil {
+		fmt.Fprintln(w, "Disk cache is disabled")
+	} else {
+		fmt.Fprintf(w, "Disk cache: %d entries (%d expired), %d bytes\n", r.Disk.Entries, r.Disk.Expired, r.Disk.Bytes)
+	}
+	return nil
+}
+
+// cacheCleanupResult is what cache clear or prune removed. DiskRemoved is how many entries
+// went from disk, which isn't touched when the disk cache is turned off
+type cacheCleanupResult struct {
+	Action      string `json:"action"`
+	DiskCache   bool   `json:"disk_cache"`
+	DiskRemoved int    `json:"disk_removed"`
+}
+
+func (r cacheCleanupResult) Table() output.Table {
+	return output.Table{
+		Columns: []string{"action", "disk_cache", "disk_removed"},
+		Rows:    [][]string{{r.Action, strconv.FormatBool(r.DiskCache), strconv.Itoa(r.DiskRemoved)}},
+	}
+}
+
+func (r cacheCleanupResult) WriteText(w io.Writer) error {
+	switch {
+	case !r.DiskCache && r.Action == "clear":
+		fmt.Fprintln(w, "Cleared the memory cache")
+	case !r.DiskCache:
+		fmt.Fprintln(w, "Disk cache is disabled")
+	case r.Action == "clear":
+		fmt.Fprintf(w, "Cleared the memory cache and %d entries from disk\n", r.DiskRemoved)
+	default:
+		fmt.Fprintf(w, "Removed %d expired entries\n", r.DiskRemoved)
+	}
+	return nil
+}